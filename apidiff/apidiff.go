@@ -0,0 +1,359 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+// Package apidiff compares two versions of an API and summarises the changes between them.
+package apidiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// Change is the type of a change.
+type Change string
+
+const (
+	Added   Change = "added"
+	Removed Change = "removed"
+	Changed Change = "changed"
+)
+
+// Object is the type of API element a change applies to.
+type Object string
+
+const (
+	GroupVersionObject Object = "groupVersion"
+	KindObject         Object = "kind"
+	TypeObject         Object = "type"
+	FieldObject        Object = "field"
+)
+
+// Attributes that can be changed.
+const (
+	kindAttribute     = "kind"
+	typeAttribute     = "type"
+	docAttribute      = "doc"
+	nameAttribute     = "name"
+	optionalAttribute = "optional"
+	enumAttribute     = "enum"
+)
+
+// Entry is a single change between the two versions of the API.
+type Entry struct {
+	Change       Change `json:"change"`
+	Object       Object `json:"object"`
+	GroupVersion string `json:"groupVersion"`
+	// Name is the name of the Kind or type, or the path of the field within its type such as
+	// "GuestbookSpec.page". It is empty for group versions.
+	Name string `json:"name,omitempty"`
+	// Attribute is the attribute that was changed: "kind", "type", "doc", "name" (the JSON name of
+	// a field), "optional" or "enum".
+	Attribute string `json:"attribute,omitempty"`
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+	// Breaking is true if clients of the old version of the API may not work with the new one.
+	Breaking bool `json:"breaking"`
+}
+
+// ID identifies the changed element as the group version followed by the name of the element,
+// such as "webapp.example.com/v1/GuestbookSpec.page".
+func (e Entry) ID() string {
+	if e.Name == "" {
+		return e.GroupVersion
+	}
+	return e.GroupVersion + "/" + e.Name
+}
+
+// Report lists the changes between two versions of an API.
+type Report struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Compare returns the changes made to the API described by old to obtain the API described by new.
+func Compare(old, new []types.GroupVersionDetails) *Report {
+	r := &Report{Entries: []Entry{}}
+
+	oldGVs := groupVersions(old)
+	newGVs := groupVersions(new)
+	for _, name := range sortedKeys(oldGVs, newGVs) {
+		o, n := oldGVs[name], newGVs[name]
+		switch {
+		case n == nil:
+			r.add(Entry{Change: Removed, Object: GroupVersionObject, GroupVersion: name, Breaking: true})
+		case o == nil:
+			r.add(Entry{Change: Added, Object: GroupVersionObject, GroupVersion: name})
+		default:
+			r.compareGroupVersion(name, o, n)
+		}
+	}
+
+	return r
+}
+
+func (r *Report) add(e Entry) {
+	r.Entries = append(r.Entries, e)
+}
+
+func (r *Report) compareGroupVersion(gv string, old, new *types.GroupVersionDetails) {
+	oldKinds := make(map[string]bool, len(old.Kinds))
+	for _, k := range old.Kinds {
+		oldKinds[k] = true
+	}
+	newKinds := make(map[string]bool, len(new.Kinds))
+	for _, k := range new.Kinds {
+		newKinds[k] = true
+	}
+	for _, k := range sortedKeys(oldKinds, newKinds) {
+		switch {
+		case !newKinds[k]:
+			r.add(Entry{Change: Removed, Object: KindObject, GroupVersion: gv, Name: k, Breaking: true})
+		case !oldKinds[k]:
+			r.add(Entry{Change: Added, Object: KindObject, GroupVersion: gv, Name: k})
+		}
+	}
+
+	for _, name := range sortedKeys(old.Types, new.Types) {
+		o, n := old.Types[name], new.Types[name]
+		switch {
+		case n == nil:
+			r.add(Entry{Change: Removed, Object: TypeObject, GroupVersion: gv, Name: name})
+		case o == nil:
+			r.add(Entry{Change: Added, Object: TypeObject, GroupVersion: gv, Name: name})
+		default:
+			r.compareType(gv, name, o, n)
+		}
+	}
+}
+
+func (r *Report) compareType(gv, name string, old, new *types.Type) {
+	if old.Kind != new.Kind {
+		r.add(Entry{Change: Changed, Object: TypeObject, GroupVersion: gv, Name: name, Attribute: kindAttribute,
+			Old: kindName(old.Kind), New: kindName(new.Kind), Breaking: true})
+	} else if old.Kind != types.StructKind && TypeName(old.UnderlyingType) != TypeName(new.UnderlyingType) {
+		r.add(Entry{Change: Changed, Object: TypeObject, GroupVersion: gv, Name: name, Attribute: typeAttribute,
+			Old: TypeName(old.UnderlyingType), New: TypeName(new.UnderlyingType),
+			Breaking: serializedTypeName(old.UnderlyingType) != serializedTypeName(new.UnderlyingType)})
+	}
+	if e, changed := compareEnums(old.Markers, new.Markers); changed {
+		e.Object, e.GroupVersion, e.Name = TypeObject, gv, name
+		r.add(e)
+	}
+	if normalizeDoc(old.Doc) != normalizeDoc(new.Doc) {
+		r.add(Entry{Change: Changed, Object: TypeObject, GroupVersion: gv, Name: name, Attribute: docAttribute,
+			Old: old.Doc, New: new.Doc})
+	}
+
+	// Aliases of structs declared in other packages have no fields of their own.
+	oldMembers, newMembers := old.Members(), new.Members()
+	oldFields := make(map[string]*types.Field, len(oldMembers))
+	for _, f := range oldMembers {
+		oldFields[f.Name] = f
+	}
+	newFields := make(map[string]*types.Field, len(newMembers))
+	for _, f := range newMembers {
+		newFields[f.Name] = f
+	}
+
+	// A field whose JSON name changed is matched by its Go name.
+	renamed := make(map[string]*types.Field)
+	for _, f := range oldMembers {
+		if newFields[f.Name] != nil {
+			continue
+		}
+		if n := findGoField(newMembers, f.GoName); n != nil && oldFields[n.Name] == nil {
+			renamed[n.Name] = f
+			continue
+		}
+		r.add(Entry{Change: Removed, Object: FieldObject, GroupVersion: gv, Name: name + "." + f.Name,
+			Old: TypeName(f.Type), Breaking: true})
+	}
+
+	for _, n := range newMembers {
+		path := name + "." + n.Name
+		o := oldFields[n.Name]
+		if o == nil {
+			if o = renamed[n.Name]; o == nil {
+				r.add(Entry{Change: Added, Object: FieldObject, GroupVersion: gv, Name: path, New: TypeName(n.Type),
+					Breaking: !n.Optional})
+				continue
+			}
+			r.add(Entry{Change: Changed, Object: FieldObject, GroupVersion: gv, Name: path, Attribute: nameAttribute,
+				Old: o.Name, New: n.Name, Breaking: true})
+		}
+		r.compareField(gv, path, o, n)
+	}
+}
+
+func (r *Report) compareField(gv, path string, old, new *types.Field) {
+	if TypeName(old.Type) != TypeName(new.Type) {
+		r.add(Entry{Change: Changed, Object: FieldObject, GroupVersion: gv, Name: path, Attribute: typeAttribute,
+			Old: TypeName(old.Type), New: TypeName(new.Type),
+			Breaking: serializedTypeName(old.Type) != serializedTypeName(new.Type)})
+	}
+	if old.Optional != new.Optional {
+		r.add(Entry{Change: Changed, Object: FieldObject, GroupVersion: gv, Name: path, Attribute: optionalAttribute,
+			Old: optionality(old.Optional), New: optionality(new.Optional), Breaking: old.Optional})
+	}
+	if e, changed := compareEnums(old.Markers, new.Markers); changed {
+		e.Object, e.GroupVersion, e.Name = FieldObject, gv, path
+		r.add(e)
+	}
+	if normalizeDoc(old.Doc) != normalizeDoc(new.Doc) {
+		r.add(Entry{Change: Changed, Object: FieldObject, GroupVersion: gv, Name: path, Attribute: docAttribute,
+			Old: old.Doc, New: new.Doc})
+	}
+}
+
+// WriteJSON writes the report to w as an indented JSON document.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report to w as a Markdown summary, with a section per group version.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("# API changes\n")
+
+	if len(r.Entries) == 0 {
+		sb.WriteString("\nNo API changes.\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	for i, e := range r.Entries {
+		if i == 0 || r.Entries[i-1].GroupVersion != e.GroupVersion {
+			fmt.Fprintf(&sb, "\n## %s\n\n", e.GroupVersion)
+		}
+		fmt.Fprintf(&sb, "- %s\n", e.summary())
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// summary describes the entry in a sentence.
+func (e Entry) summary() string {
+	if e.Object == GroupVersionObject {
+		if e.Breaking {
+			return fmt.Sprintf("**Breaking:** Group version %s", e.Change)
+		}
+		return fmt.Sprintf("Group version %s", e.Change)
+	}
+
+	object := map[Object]string{KindObject: "Kind", TypeObject: "Type", FieldObject: "Field"}[e.Object]
+	if e.Breaking {
+		object = "**Breaking:** " + object
+	}
+	switch {
+	case e.Change == Added && e.New != "":
+		return fmt.Sprintf("%s `%s` added, of type `%s`", object, e.Name, e.New)
+	case e.Change == Removed && e.Old != "":
+		return fmt.Sprintf("%s `%s` removed, was of type `%s`", object, e.Name, e.Old)
+	case e.Attribute == docAttribute:
+		return fmt.Sprintf("%s `%s` documentation changed", object, e.Name)
+	case e.Attribute != "":
+		return fmt.Sprintf("%s `%s` %s changed from `%s` to `%s`", object, e.Name, e.Attribute, e.Old, e.New)
+	default:
+		return fmt.Sprintf("%s `%s` %s", object, e.Name, e.Change)
+	}
+}
+
+// TypeName returns the name of a type as written in Go, with the package omitted.
+func TypeName(t *types.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		return "*" + TypeName(t.UnderlyingType)
+	case types.SliceKind, types.ArrayKind:
+		return "[]" + TypeName(t.UnderlyingType)
+	case types.MapKind:
+		return fmt.Sprintf("map[%s]%s", TypeName(t.KeyType), TypeName(t.ValueType))
+	default:
+		if t.Name == "" {
+			return "struct{...}"
+		}
+		return t.Name
+	}
+}
+
+// serializedTypeName returns the name of a type without its pointers, which do not change how
+// values are serialized.
+func serializedTypeName(t *types.Type) string {
+	return strings.ReplaceAll(TypeName(t), "*", "")
+}
+
+func findGoField(fields types.Fields, goName string) *types.Field {
+	if goName == "" {
+		return nil
+	}
+	for _, f := range fields {
+		if f.GoName == goName {
+			return f
+		}
+	}
+	return nil
+}
+
+func optionality(optional bool) string {
+	if optional {
+		return "optional"
+	}
+	return "required"
+}
+
+func kindName(k types.Kind) string {
+	b, _ := k.MarshalJSON()
+	return strings.ToLower(strings.Trim(string(b), `"`))
+}
+
+// normalizeDoc ignores differences in whitespace between two versions of a comment.
+func normalizeDoc(doc string) string {
+	return strings.Join(strings.Fields(doc), " ")
+}
+
+func groupVersions(gvd []types.GroupVersionDetails) map[string]*types.GroupVersionDetails {
+	m := make(map[string]*types.GroupVersionDetails, len(gvd))
+	for i := range gvd {
+		m[gvd[i].GroupVersionString()] = &gvd[i]
+	}
+	return m
+}
+
+// sortedKeys returns the keys of both maps, sorted.
+func sortedKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var keys []string
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
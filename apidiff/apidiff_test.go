@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package apidiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testAPI(mutate func(spec *types.Type, gv *types.GroupVersionDetails)) []types.GroupVersionDetails {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind, Doc: "ThingSpec is the spec."}
+	spec.Fields = types.Fields{
+		{Name: "name", Type: str, Doc: "Name of the thing."},
+		{Name: "size", Type: &types.Type{Kind: types.PointerKind, UnderlyingType: &types.Type{Name: "int", Kind: types.BasicKind}}},
+	}
+	thing := &types.Type{Name: "Thing", Package: "example.com/api/v1", Kind: types.StructKind, Fields: types.Fields{{Name: "spec", Type: spec}}}
+	gv := types.GroupVersionDetails{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec},
+	}
+	if mutate != nil {
+		mutate(spec, &gv)
+	}
+	return []types.GroupVersionDetails{gv}
+}
+
+func TestCompare(t *testing.T) {
+	old := testAPI(nil)
+	new := testAPI(func(spec *types.Type, gv *types.GroupVersionDetails) {
+		spec.Doc = "ThingSpec is the\n  spec."
+		spec.Fields[0].Doc = "Name of the thing, unique in its namespace."
+		spec.Fields[1].Type = &types.Type{Kind: types.PointerKind, UnderlyingType: &types.Type{Name: "int32", Kind: types.BasicKind}}
+		spec.Fields = append(spec.Fields, &types.Field{Name: "labels", Optional: true, Type: &types.Type{Kind: types.MapKind,
+			KeyType: &types.Type{Name: "string", Kind: types.BasicKind}, ValueType: &types.Type{Name: "string", Kind: types.BasicKind}}})
+		gv.Kinds = append(gv.Kinds, "Other")
+		gv.Types["Other"] = &types.Type{Name: "Other", Package: "example.com/api/v1", Kind: types.StructKind}
+	})
+	new = append(new, types.GroupVersionDetails{GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v2"}})
+
+	report := Compare(old, new)
+	require.Equal(t, []Entry{
+		{Change: Added, Object: KindObject, GroupVersion: "example.com/v1", Name: "Other"},
+		{Change: Added, Object: TypeObject, GroupVersion: "example.com/v1", Name: "Other"},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.name", Attribute: "doc",
+			Old: "Name of the thing.", New: "Name of the thing, unique in its namespace."},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.size", Attribute: "type",
+			Old: "*int", New: "*int32", Breaking: true},
+		{Change: Added, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.labels", New: "map[string]string"},
+		{Change: Added, Object: GroupVersionObject, GroupVersion: "example.com/v2"},
+	}, report.Entries)
+
+	var sb strings.Builder
+	require.NoError(t, report.WriteMarkdown(&sb))
+	require.Equal(t, "# API changes\n"+
+		"\n## example.com/v1\n\n"+
+		"- Kind `Other` added\n"+
+		"- Type `Other` added\n"+
+		"- Field `ThingSpec.name` documentation changed\n"+
+		"- **Breaking:** Field `ThingSpec.size` type changed from `*int` to `*int32`\n"+
+		"- Field `ThingSpec.labels` added, of type `map[string]string`\n"+
+		"\n## example.com/v2\n\n"+
+		"- Group version added\n", sb.String())
+}
+
+func TestCompareRemoved(t *testing.T) {
+	old := testAPI(nil)
+	new := testAPI(func(spec *types.Type, gv *types.GroupVersionDetails) {
+		spec.Fields = spec.Fields[:1]
+		gv.Kinds = nil
+	})
+
+	require.Equal(t, []Entry{
+		{Change: Removed, Object: KindObject, GroupVersion: "example.com/v1", Name: "Thing", Breaking: true},
+		{Change: Removed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.size", Old: "*int", Breaking: true},
+	}, Compare(old, new).Entries)
+
+	var sb strings.Builder
+	require.NoError(t, Compare(old, old).WriteMarkdown(&sb))
+	require.Equal(t, "# API changes\n\nNo API changes.\n", sb.String())
+}
+
+func TestComparePointers(t *testing.T) {
+	old := testAPI(func(spec *types.Type, gv *types.GroupVersionDetails) {
+		gv.Types["Size"] = &types.Type{Name: "Size", Package: "example.com/api/v1", Kind: types.AliasKind,
+			UnderlyingType: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Kind: types.PointerKind, UnderlyingType: &types.Type{Name: "int", Kind: types.BasicKind}}}}
+	})
+	new := testAPI(func(spec *types.Type, gv *types.GroupVersionDetails) {
+		spec.Fields[1].Type = &types.Type{Name: "int", Kind: types.BasicKind}
+		gv.Types["Size"] = &types.Type{Name: "Size", Package: "example.com/api/v1", Kind: types.AliasKind,
+			UnderlyingType: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Name: "int", Kind: types.BasicKind}}}
+	})
+
+	report := Compare(old, new)
+	require.Equal(t, []Entry{
+		{Change: Changed, Object: TypeObject, GroupVersion: "example.com/v1", Name: "Size", Attribute: "type", Old: "[]*int", New: "[]int"},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.size", Attribute: "type", Old: "*int", New: "int"},
+	}, report.Entries)
+
+	breaking, err := report.BreakingChanges(map[string]bool{"example.com/v1": true}, nil)
+	require.NoError(t, err)
+	require.Empty(t, breaking.Entries)
+}
+
+func TestCompareMembers(t *testing.T) {
+	resources := func(fields ...*types.Field) *types.Type {
+		return &types.Type{Name: "Resources", Package: "example.com/common", Kind: types.StructKind, Fields: fields}
+	}
+	limits := func(underlying *types.Type) func(spec *types.Type, gv *types.GroupVersionDetails) {
+		return func(spec *types.Type, gv *types.GroupVersionDetails) {
+			gv.Types["Limits"] = &types.Type{Name: "Limits", Package: "example.com/api/v1", Kind: types.AliasKind, UnderlyingType: underlying}
+		}
+	}
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	old := testAPI(limits(resources(&types.Field{Name: "cpu", Type: str}, &types.Field{Name: "memory", Type: str})))
+	new := testAPI(limits(resources(&types.Field{Name: "cpu", Type: str})))
+
+	require.Equal(t, []Entry{
+		{Change: Removed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "Limits.memory", Old: "string", Breaking: true},
+	}, Compare(old, new).Entries)
+}
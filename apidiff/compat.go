@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package apidiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+const unservedVersionMarker = "kubebuilder:unservedversion"
+
+// compareEnums compares the values allowed by the enum markers among the given markers. Removing
+// allowed values, or restricting a field that was not an enum, is a breaking change.
+func compareEnums(old, new markers.MarkerValues) (Entry, bool) {
+	oldValues, oldOK := enumValues(old)
+	newValues, newOK := enumValues(new)
+	if !oldOK && !newOK {
+		return Entry{}, false
+	}
+
+	allowed := make(map[string]bool, len(newValues))
+	for _, v := range newValues {
+		allowed[v] = true
+	}
+
+	narrowed := oldOK != newOK && newOK
+	for _, v := range oldValues {
+		narrowed = narrowed || (newOK && !allowed[v])
+	}
+
+	e := Entry{Change: Changed, Attribute: enumAttribute, Old: strings.Join(oldValues, ", "), New: strings.Join(newValues, ", "), Breaking: narrowed}
+	return e, e.Old != e.New || oldOK != newOK
+}
+
+func enumValues(values markers.MarkerValues) ([]string, bool) {
+	enum, ok := values.Get(types.EnumMarker).(crdmarkers.Enum)
+	if !ok {
+		return nil, false
+	}
+
+	list := make([]string, len(enum))
+	for i, v := range enum {
+		list[i] = fmt.Sprint(v)
+	}
+	return list, true
+}
+
+// ServedGroupVersions returns the group versions of the API that are served, that is which
+// declare at least one Kind without the +kubebuilder:unservedversion marker.
+func ServedGroupVersions(gvd []types.GroupVersionDetails) map[string]bool {
+	served := make(map[string]bool)
+	for _, gv := range gvd {
+		for _, kind := range gv.Kinds {
+			if t := gv.TypeForKind(kind); t != nil && t.Markers.Get(unservedVersionMarker) == nil {
+				served[gv.GroupVersionString()] = true
+			}
+		}
+	}
+	return served
+}
+
+// BreakingChanges returns the breaking changes of the report made to the given served group
+// versions, except the ones whose ID matches one of the allowed regular expressions.
+func (r *Report) BreakingChanges(served map[string]bool, allowed []string) (*Report, error) {
+	allowedRegexes := make([]*regexp.Regexp, len(allowed))
+	for i, a := range allowed {
+		re, err := regexp.Compile(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed breaking change %q: %w", a, err)
+		}
+		allowedRegexes[i] = re
+	}
+
+	breaking := &Report{Entries: []Entry{}}
+entries:
+	for _, e := range r.Entries {
+		if !e.Breaking || !served[e.GroupVersion] {
+			continue
+		}
+		for _, re := range allowedRegexes {
+			if re.MatchString(e.ID()) {
+				continue entries
+			}
+		}
+		breaking.add(e)
+	}
+	return breaking, nil
+}
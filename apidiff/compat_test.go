@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package apidiff
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestBreakingChanges(t *testing.T) {
+	level := func(values ...interface{}) markers.MarkerValues {
+		return markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum(values)}}
+	}
+	old := testAPI(func(spec *types.Type, _ *types.GroupVersionDetails) {
+		spec.Fields[0].GoName = "Name"
+		spec.Fields[0].Optional = true
+		spec.Fields = append(spec.Fields,
+			&types.Field{Name: "level", Type: &types.Type{Name: "string", Kind: types.BasicKind}, Markers: level("low", "high")},
+			&types.Field{Name: "mode", Type: &types.Type{Name: "string", Kind: types.BasicKind}, Markers: level("a")})
+	})
+	new := testAPI(func(spec *types.Type, _ *types.GroupVersionDetails) {
+		spec.Fields[0].Name = "fullName"
+		spec.Fields[0].GoName = "Name"
+		spec.Fields = append(spec.Fields,
+			&types.Field{Name: "level", Type: &types.Type{Name: "string", Kind: types.BasicKind}, Markers: level("low")},
+			&types.Field{Name: "mode", Type: &types.Type{Name: "string", Kind: types.BasicKind}, Markers: level("a", "b")})
+	})
+
+	report := Compare(old, new)
+	require.Equal(t, []Entry{
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.fullName", Attribute: "name",
+			Old: "name", New: "fullName", Breaking: true},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.fullName", Attribute: "optional",
+			Old: "optional", New: "required", Breaking: true},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.level", Attribute: "enum",
+			Old: "low, high", New: "low", Breaking: true},
+		{Change: Changed, Object: FieldObject, GroupVersion: "example.com/v1", Name: "ThingSpec.mode", Attribute: "enum",
+			Old: "a", New: "a, b"},
+	}, report.Entries)
+
+	served := ServedGroupVersions(old)
+	require.Equal(t, map[string]bool{"example.com/v1": true}, served)
+
+	breaking, err := report.BreakingChanges(served, []string{`/ThingSpec\.level$`})
+	require.NoError(t, err)
+	require.Len(t, breaking.Entries, 2)
+	require.Equal(t, "example.com/v1/ThingSpec.fullName", breaking.Entries[0].ID())
+
+	old[0].Types["Thing"].Markers = markers.MarkerValues{unservedVersionMarker: {crdmarkers.UnservedVersion{}}}
+	breaking, err = report.BreakingChanges(ServedGroupVersions(old), nil)
+	require.NoError(t, err)
+	require.Empty(t, breaking.Entries)
+
+	_, err = report.BreakingChanges(served, []string{"("})
+	require.Error(t, err)
+}
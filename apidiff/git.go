@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package apidiff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree checks out the given revision of the git repository containing paths in a temporary
+// worktree. It returns the locations of paths within the worktree, and a function removing the
+// worktree once it is no longer needed.
+func Worktree(paths []string, revision string) ([]string, func() error, error) {
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("no path to check out")
+	}
+
+	var top string
+	rels := make([]string, len(paths))
+	for i, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pathTop, err := git(abs, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return nil, nil, err
+		}
+		if top == "" {
+			top = pathTop
+		} else if pathTop != top {
+			return nil, nil, fmt.Errorf("%s is not in the git repository %s", path, top)
+		}
+
+		if rels[i], err = filepath.Rel(top, abs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "crd-ref-docs-worktree-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := git(top, "worktree", "add", "--detach", dir, revision); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, err
+	}
+
+	cleanup := func() error {
+		_, err := git(top, "worktree", "remove", "--force", dir)
+		return err
+	}
+
+	worktreePaths := make([]string, len(rels))
+	for i, rel := range rels {
+		worktreePaths[i] = filepath.Join(dir, rel)
+	}
+	return worktreePaths, cleanup, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
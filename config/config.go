@@ -17,7 +17,10 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/goccy/go-yaml"
 )
@@ -25,7 +28,12 @@ import (
 type Config struct {
 	Processor ProcessorConfig `json:"processor"`
 	Render    RenderConfig    `json:"render"`
+	Compat    CompatConfig    `json:"compat"`
+	Lint      LintConfig      `json:"lint"`
 	Flags     `json:"-"`
+	// Overlay maps absolute file paths to contents replacing the files on disk when loading
+	// packages, such as the contents of unsaved files or of files changed by a patch.
+	Overlay map[string][]byte `json:"-"`
 }
 
 type ProcessorConfig struct {
@@ -34,11 +42,62 @@ type ProcessorConfig struct {
 	IgnoreFields        []string `json:"ignoreFields"`
 	IgnoreGroupVersions []string `json:"ignoreGroupVersions"`
 	UseRawDocstring     bool     `json:"useRawDocstring"`
+	// SamplesPath is a file or directory of sample manifests to attach to their Kinds.
+	// Relative paths are resolved against the directory of the configuration file.
+	SamplesPath string `json:"samplesPath"`
+	// Releases are the git tags of the releases of the API, oldest first. When set, Kinds, types
+	// and fields are annotated with the releases in which they were introduced, deprecated and
+	// removed, by processing every release of the repository containing the source path.
+	Releases []string `json:"releases"`
+	// Packages are the patterns of the packages to load, relative to the source path. Defaults to
+	// "./...".
+	Packages []string `json:"packages"`
+	// ExcludeDirs are glob patterns of directories, relative to the source path, whose packages
+	// are not processed. Patterns also exclude the subdirectories of the directories they match.
+	ExcludeDirs []string `json:"excludeDirs"`
+	// BuildTags are the build tags to set when loading packages.
+	BuildTags []string `json:"buildTags"`
+	// GoFlags are flags to add to GOFLAGS when loading packages, such as "-mod=vendor".
+	GoFlags []string `json:"goFlags"`
+	// KindDetection are the strategies detecting the types that are Kinds: "objectRoot" for types
+	// marked with +kubebuilder:object:root, "genclient" for types marked with +genclient and
+	// "schemeRegistration" for types registered with a scheme by their package. Defaults to
+	// "objectRoot".
+	KindDetection []string `json:"kindDetection"`
+	// CollapseListKinds omits the List Kinds, root objects with list metadata and a list of items
+	// of another Kind, and sets the ListKind of the Kinds they list instead.
+	CollapseListKinds bool `json:"collapseListKinds"`
+	// SourcePaths are the source directories containing CRDs, used when no source path is given
+	// on the command line. Relative paths are resolved against the directory of the configuration
+	// file.
+	SourcePaths []string `json:"sourcePaths"`
 }
 
 type RenderConfig struct {
-	KnownTypes        []*KnownType `json:"knownTypes"`
-	KubernetesVersion string       `json:"kubernetesVersion"`
+	KnownTypes        []*KnownType  `json:"knownTypes"`
+	KubernetesVersion string        `json:"kubernetesVersion"`
+	AnchorPrefix      string        `json:"anchorPrefix"`
+	Antora            *AntoraConfig `json:"antora"`
+}
+
+// AntoraConfig enables writing the asciidoctor output as an Antora module.
+type AntoraConfig struct {
+	// Module is the name of the Antora module the pages are written into.
+	Module string `json:"module"`
+}
+
+// CompatConfig configures the detection of breaking changes.
+type CompatConfig struct {
+	// AllowedBreakingChanges are RE2 regular expressions matching the breaking changes that are
+	// accepted, identified by their group version and the name of the changed element, for
+	// example "webapp.example.com/v1/GuestbookSpec\.page$".
+	AllowedBreakingChanges []string `json:"allowedBreakingChanges"`
+}
+
+// LintConfig configures the documentation linter.
+type LintConfig struct {
+	// MinCoverage is the minimum percentage of documented types and fields of every group version.
+	MinCoverage float64 `json:"minCoverage"`
 }
 
 type KnownType struct {
@@ -48,11 +107,15 @@ type KnownType struct {
 }
 
 type Flags struct {
+	CacheDir     string
+	Check        bool
 	Config       string
+	ExamplesDir  string
 	LogLevel     string
 	OutputPath   string
+	Overlay      string
 	Renderer     string
-	SourcePath   string
+	SourcePaths  []string
 	TemplatesDir string
 	MaxDepth     int
 }
@@ -70,6 +133,61 @@ func Load(flags Flags) (*Config, error) {
 		return nil, err
 	}
 
+	if conf.Processor.SamplesPath != "" && !filepath.IsAbs(conf.Processor.SamplesPath) {
+		conf.Processor.SamplesPath = filepath.Join(filepath.Dir(flags.Config), conf.Processor.SamplesPath)
+	}
+
+	for i, path := range conf.Processor.SourcePaths {
+		if !filepath.IsAbs(path) {
+			conf.Processor.SourcePaths[i] = filepath.Join(filepath.Dir(flags.Config), path)
+		}
+	}
+
+	if flags.Overlay != "" {
+		if conf.Overlay, err = LoadOverlay(flags.Overlay); err != nil {
+			return nil, fmt.Errorf("failed to read overlay %s: %w", flags.Overlay, err)
+		}
+	}
+
 	conf.Flags = flags
+	if len(conf.SourcePaths) == 0 {
+		conf.SourcePaths = conf.Processor.SourcePaths
+	}
+	if len(conf.SourcePaths) == 0 {
+		conf.SourcePaths = []string{"."}
+	}
 	return &conf, nil
 }
+
+// LoadOverlay reads an overlay file in the format of the -overlay flag of go build, a JSON object
+// whose Replace field maps the paths of files to the paths of the files replacing them. It
+// returns the contents of the replacing files by the absolute paths of the files they replace.
+func LoadOverlay(path string) (map[string][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlay struct {
+		Replace map[string]string
+	}
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return nil, err
+	}
+
+	contents := make(map[string][]byte, len(overlay.Replace))
+	for file, replacement := range overlay.Replace {
+		if replacement == "" {
+			return nil, fmt.Errorf("removing %s is not supported", file)
+		}
+
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		if contents[abs], err = os.ReadFile(replacement); err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
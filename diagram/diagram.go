@@ -0,0 +1,243 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package diagram draws the types making up a Kind as Mermaid class diagrams and Graphviz graphs.
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// kubePackagePrefix is the prefix of the packages whose types are drawn without their fields.
+const kubePackagePrefix = "k8s.io/"
+
+var idRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Graph is the graph of the struct types making up a Kind, in which the edges are the fields
+// referring to other struct types.
+type Graph struct {
+	Nodes []*Node
+	Edges []Edge
+}
+
+// Node is a struct type of the graph.
+type Node struct {
+	ID   string
+	Type *types.Type
+	// Attributes are the fields of the type that are not edges.
+	Attributes []Attribute
+}
+
+// Attribute is a field of a node that does not refer to a struct type.
+type Attribute struct {
+	Name string
+	Type string
+}
+
+// Edge is a field referring to another struct type.
+type Edge struct {
+	From  *Node
+	To    *Node
+	Label string
+	// Many is true if the field holds a collection of values.
+	Many bool
+}
+
+// New returns the graph of the types reachable from the given root type through the fields of
+// struct types, up to maxDepth levels away from the root. Types from Kubernetes packages are drawn
+// without their fields.
+//
+// The graph follows the fields of the types rather than their References: references lead from a
+// type to the types of the processed group versions that refer to it, so drawing a Kind from them
+// would require every type of the group versions, and would leave out the types of other packages,
+// such as ObjectMeta, that the Kind is made of.
+func New(root *types.Type, maxDepth int) *Graph {
+	g := &Graph{}
+	nodes := make(map[string]*Node)
+	ids := make(map[string]bool)
+
+	var visit func(t *types.Type, depth int) *Node
+	visit = func(t *types.Type, depth int) *Node {
+		key := types.Key(t)
+		if n, ok := nodes[key]; ok {
+			return n
+		}
+
+		n := &Node{ID: uniqueID(t.Name, ids), Type: t}
+		nodes[key] = n
+		g.Nodes = append(g.Nodes, n)
+
+		if strings.HasPrefix(t.Package, kubePackagePrefix) || depth >= maxDepth {
+			return n
+		}
+
+		for _, f := range t.Members() {
+			target, many := structType(f.Type)
+			if target == nil {
+				n.Attributes = append(n.Attributes, Attribute{Name: f.Name, Type: typeName(f.Type)})
+				continue
+			}
+
+			g.Edges = append(g.Edges, Edge{From: n, To: visit(target, depth+1), Label: f.Name, Many: many})
+		}
+
+		return n
+	}
+
+	visit(root, 0)
+	return g
+}
+
+// structType returns the named struct type held by values of the given type, if any, and whether
+// the values are collections of them.
+func structType(t *types.Type) (*types.Type, bool) {
+	many := false
+	for t != nil {
+		switch t.Kind {
+		case types.PointerKind:
+			t = t.UnderlyingType
+		case types.SliceKind, types.ArrayKind:
+			many = true
+			t = t.UnderlyingType
+		case types.MapKind:
+			many = true
+			t = t.ValueType
+		case types.StructKind:
+			if t.Name == "" || t.Package == "" {
+				return nil, false
+			}
+			return t, many
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// typeName returns the short name of a type, as written in Go.
+func typeName(t *types.Type) string {
+	if t == nil {
+		return "?"
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		return "*" + typeName(t.UnderlyingType)
+	case types.SliceKind, types.ArrayKind:
+		return "[]" + typeName(t.UnderlyingType)
+	case types.MapKind:
+		return fmt.Sprintf("map[%s]%s", typeName(t.KeyType), typeName(t.ValueType))
+	default:
+		if t.Name == "" {
+			return "struct"
+		}
+		return t.Name
+	}
+}
+
+func uniqueID(name string, ids map[string]bool) string {
+	base := idRegex.ReplaceAllString(name, "_")
+	if base == "" {
+		base = "Type"
+	}
+
+	id := base
+	for i := 2; ids[id]; i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	ids[id] = true
+	return id
+}
+
+// Mermaid returns the graph as a Mermaid class diagram.
+func (g *Graph) Mermaid() string {
+	var sb strings.Builder
+	sb.WriteString("classDiagram\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "  class %s", n.ID)
+		if n.ID != n.Type.Name {
+			fmt.Fprintf(&sb, "[%q]", n.Type.Name)
+		}
+		if len(n.Attributes) == 0 {
+			sb.WriteString("\n")
+			continue
+		}
+
+		sb.WriteString(" {\n")
+		for _, a := range n.Attributes {
+			fmt.Fprintf(&sb, "    +%s %s\n", mermaidEscape(a.Type), a.Name)
+		}
+		sb.WriteString("  }\n")
+	}
+
+	for _, e := range g.Edges {
+		cardinality := ""
+		if e.Many {
+			cardinality = `"*" `
+		}
+		fmt.Fprintf(&sb, "  %s --> %s%s : %s\n", e.From.ID, cardinality, e.To.ID, e.Label)
+	}
+
+	return sb.String()
+}
+
+// mermaidEscape replaces the characters that Mermaid would interpret in class members.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer("{", "#123;", "}", "#125;", "~", "#126;").Replace(s)
+}
+
+// DOT returns the graph as a Graphviz digraph named after the given name.
+func (g *Graph) DOT(name string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph %q {\n", name)
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=record, fontname=\"Helvetica\"];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\"];\n")
+
+	for _, n := range g.Nodes {
+		label := dotEscape(n.Type.Name)
+		if len(n.Attributes) > 0 {
+			attrs := make([]string, len(n.Attributes))
+			for i, a := range n.Attributes {
+				attrs[i] = dotEscape(a.Name+": "+a.Type) + `\l`
+			}
+			label = "{" + label + "|" + strings.Join(attrs, "") + "}"
+		}
+		fmt.Fprintf(&sb, "  %s [label=\"%s\"];\n", n.ID, label)
+	}
+
+	for _, e := range g.Edges {
+		label := e.Label
+		if e.Many {
+			label += " [*]"
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [label=%q];\n", e.From.ID, e.To.ID, label)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotEscape escapes the characters that are special in the labels of record nodes.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(s)
+}
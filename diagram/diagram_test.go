@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package diagram
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testKind() *types.Type {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	meta := &types.Type{Name: "ObjectMeta", Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Kind: types.StructKind}
+	meta.Fields = types.Fields{{Name: "name", Type: str}}
+	port := &types.Type{Name: "Port", Package: "example.com/api/v1", Kind: types.StructKind}
+	port.Fields = types.Fields{{Name: "name", Type: str}}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "image", Type: str},
+		{Name: "labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}},
+		{Name: "ports", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: port}},
+		{Name: "main", Type: &types.Type{Kind: types.PointerKind, UnderlyingType: port}},
+	}
+	return &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Fields: types.Fields{
+			{Name: "metadata", Type: meta},
+			{Name: "spec", Type: spec},
+		},
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	require.Equal(t, `classDiagram
+  class Thing
+  class ObjectMeta
+  class ThingSpec {
+    +string image
+    +map[string]string labels
+  }
+  class Port {
+    +string name
+  }
+  Thing --> ObjectMeta : metadata
+  ThingSpec --> "*" Port : ports
+  ThingSpec --> Port : main
+  Thing --> ThingSpec : spec
+`, New(testKind(), 10).Mermaid())
+}
+
+func TestDOT(t *testing.T) {
+	require.Equal(t, `digraph "Thing" {
+  rankdir=LR;
+  node [shape=record, fontname="Helvetica"];
+  edge [fontname="Helvetica"];
+  Thing [label="Thing"];
+  ObjectMeta [label="ObjectMeta"];
+  ThingSpec [label="{ThingSpec|image: string\llabels: map[string]string\l}"];
+  Port [label="Port"];
+  Thing -> ObjectMeta [label="metadata"];
+  ThingSpec -> Port [label="ports [*]"];
+  ThingSpec -> Port [label="main"];
+  Thing -> ThingSpec [label="spec"];
+}
+`, New(testKind(), 2).DOT("Thing"))
+}
@@ -0,0 +1,288 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package example generates example manifests for Kinds from the processed types.
+package example
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/goccy/go-yaml"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+const (
+	exampleMarker = "kubebuilder:example"
+	indent        = "  "
+)
+
+// wellKnownValues holds the sample values of types whose Go representation differs from their
+// serialized form.
+var wellKnownValues = map[string]string{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                          `"2006-01-02T15:04:05Z"`,
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                     `"2006-01-02T15:04:05.000000Z"`,
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                      "1m0s",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                    "{}",
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                      `"1"`,
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                    "0",
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                       "{}",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON":      "{}",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1.JSON": "{}",
+}
+
+// Generate returns an example manifest of the Kind described by the given root type. Every field
+// is set to its example or default value, the first of its allowed values, or a sample value
+// based on its type. The documentation of fields is included as comments.
+func Generate(t *types.Type, maxDepth int) string {
+	if t == nil || t.GVK == nil {
+		return ""
+	}
+
+	g := &generator{maxDepth: maxDepth, seen: map[string]bool{types.Key(t): true}}
+	g.line(0, "apiVersion: "+t.GVK.GroupVersion().String())
+	g.line(0, "kind: "+t.GVK.Kind)
+	g.line(0, "metadata:")
+	g.line(1, "name: "+strings.ToLower(t.GVK.Kind)+"-sample")
+
+	for _, f := range t.Members() {
+		switch f.Name {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		g.field(f, 0)
+	}
+
+	return g.sb.String()
+}
+
+// WriteFiles writes an example manifest for every Kind in the given group versions into dir.
+func WriteFiles(dir string, gvd []types.GroupVersionDetails, maxDepth int) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, gv := range gvd {
+		for _, k := range gv.SortedKinds() {
+			t := gv.TypeForKind(k)
+			if t == nil || t.GVK == nil {
+				continue
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, FileName(t)), []byte(Generate(t, maxDepth)), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FileName returns the name of the file holding the example of the given root type.
+func FileName(t *types.Type) string {
+	return fmt.Sprintf("%s_%s_%s.yaml", t.GVK.Group, t.GVK.Version, strings.ToLower(t.GVK.Kind))
+}
+
+type generator struct {
+	sb       strings.Builder
+	maxDepth int
+	// seen holds the keys of the types on the current path, to stop at recursive types
+	seen map[string]bool
+}
+
+func (g *generator) line(level int, text string) {
+	g.sb.WriteString(strings.Repeat(indent, level))
+	g.sb.WriteString(text)
+	g.sb.WriteString("\n")
+}
+
+func (g *generator) comment(level int, doc string) {
+	for _, l := range strings.Split(strings.TrimSpace(doc), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			g.line(level, "# "+l)
+		} else if doc != "" {
+			g.line(level, "#")
+		}
+	}
+}
+
+func (g *generator) field(f *types.Field, level int) {
+	g.comment(level, f.Doc)
+
+	if v, ok := explicitValue(f); ok {
+		g.scalar(level, f.Name+":", v)
+		return
+	}
+
+	g.value(level, f.Name+":", f.Type, f.Markers)
+}
+
+// value writes the sample value of type t after the given key, followed by nested lines if the
+// value is a list or an object.
+func (g *generator) value(level int, key string, t *types.Type, fieldMarkers markers.MarkerValues) {
+	if t == nil {
+		g.line(level, key+" {}")
+		return
+	}
+
+	if v, ok := wellKnownValues[types.Key(t)]; ok {
+		g.line(level, key+" "+v)
+		return
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		g.value(level, key, t.UnderlyingType, fieldMarkers)
+
+	case types.BasicKind, types.AliasKind:
+		g.scalar(level, key, basicValue(t, fieldMarkers))
+
+	case types.SliceKind, types.ArrayKind:
+		g.line(level, key)
+		g.listItem(level+1, t.UnderlyingType, fieldMarkers)
+
+	case types.MapKind:
+		g.line(level, key)
+		g.value(level+1, "key:", t.ValueType, nil)
+
+	case types.StructKind:
+		k := types.Key(t)
+		if g.seen[k] || len(g.seen) > g.maxDepth || len(t.Members()) == 0 {
+			g.line(level, key+" {}")
+			return
+		}
+
+		g.seen[k] = true
+		g.line(level, key)
+		for _, f := range t.Members() {
+			g.field(f, level+1)
+		}
+		delete(g.seen, k)
+
+	default:
+		g.line(level, key+" {}")
+	}
+}
+
+// listItem writes a single element of a list of type t.
+func (g *generator) listItem(level int, t *types.Type, fieldMarkers markers.MarkerValues) {
+	// render the item as an object at the nesting level of its fields, then turn the line of its
+	// first field into the start of the list item
+	sub := &generator{maxDepth: g.maxDepth, seen: g.seen}
+	sub.value(level, "", t, fieldMarkers)
+
+	itemIndent := strings.Repeat(indent, level)
+	lines := strings.Split(strings.TrimSuffix(sub.sb.String(), "\n"), "\n")
+	if strings.TrimSpace(lines[0]) != "" {
+		// scalar value rendered on the key line
+		g.line(level, "- "+strings.TrimSpace(lines[0]))
+		for _, l := range lines[1:] {
+			g.sb.WriteString(l + "\n")
+		}
+		return
+	}
+
+	started := false
+	for _, l := range lines[1:] {
+		content := strings.TrimPrefix(l, itemIndent+indent)
+		switch {
+		case started:
+			g.sb.WriteString(l + "\n")
+		case strings.HasPrefix(content, "#"):
+			g.sb.WriteString(itemIndent + content + "\n")
+		default:
+			g.sb.WriteString(itemIndent + "- " + content + "\n")
+			started = true
+		}
+	}
+}
+
+// scalar writes the given value after the key, as a block below the key if it does not fit on a line.
+func (g *generator) scalar(level int, key string, v any) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		out = []byte(fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+
+	s := strings.TrimSuffix(string(out), "\n")
+	if !strings.Contains(s, "\n") {
+		g.line(level, strings.TrimSpace(key+" "+s))
+		return
+	}
+
+	g.line(level, key)
+	for _, l := range strings.Split(s, "\n") {
+		g.line(level+1, l)
+	}
+}
+
+// explicitValue returns the example or default value of a field, if it declares one.
+func explicitValue(f *types.Field) (any, bool) {
+	if e, ok := f.Markers.Get(exampleMarker).(crdmarkers.Example); ok {
+		return e.Value, true
+	}
+
+	if f.Default != nil {
+		return f.Default, true
+	}
+
+	return nil, false
+}
+
+// basicValue returns a sample value for a basic type or an alias of a basic type.
+func basicValue(t *types.Type, fieldMarkers markers.MarkerValues) any {
+	for _, m := range []markers.MarkerValues{fieldMarkers, t.Markers} {
+		if enum, ok := m.Get(types.EnumMarker).(crdmarkers.Enum); ok && len(enum) > 0 {
+			return enum[0]
+		}
+	}
+
+	name := t.Name
+	if t.Kind == types.AliasKind && t.UnderlyingType != nil {
+		name = t.UnderlyingType.Name
+	}
+
+	var minimum *float64
+	for _, m := range []markers.MarkerValues{fieldMarkers, t.Markers} {
+		if v, ok := m.Get(types.MinimumMarker).(crdmarkers.Minimum); ok {
+			min := v.Value()
+			minimum = &min
+			break
+		}
+	}
+
+	switch name {
+	case "bool":
+		return false
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		if minimum != nil {
+			return int64(*minimum)
+		}
+		return 0
+	case "float32", "float64":
+		if minimum != nil {
+			return *minimum
+		}
+		return 0.0
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package example
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestGenerate(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	integer := &types.Type{Name: "int32", Kind: types.BasicKind}
+	level := &types.Type{
+		Name:           "Level",
+		Package:        "example.com/api/v1",
+		Kind:           types.AliasKind,
+		UnderlyingType: str,
+		Markers:        markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum{"low", "high"}}},
+	}
+	item := &types.Type{Name: "Item", Package: "example.com/api/v1", Kind: types.StructKind}
+	item.Fields = types.Fields{
+		{Name: "name", Type: str, Doc: "Name of the item."},
+		{Name: "level", Type: level},
+	}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "replicas", Type: integer, Default: 3},
+		{Name: "size", Type: integer, Markers: markers.MarkerValues{types.MinimumMarker: {crdmarkers.Minimum(2)}}},
+		{Name: "items", Type: &types.Type{Name: "Item", Package: "example.com/api/v1", Kind: types.SliceKind, UnderlyingType: item}},
+		{Name: "labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}},
+		{Name: "parent", Type: &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.PointerKind, UnderlyingType: spec}},
+	}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}, {Name: "status", Type: str}},
+	}
+
+	expected := `apiVersion: example.com/v1
+kind: Thing
+metadata:
+  name: thing-sample
+spec:
+  replicas: 3
+  size: 2
+  items:
+    # Name of the item.
+    - name: string
+      level: low
+  labels:
+    key: string
+  parent: {}
+`
+	out := Generate(thing, 5)
+	require.Equal(t, expected, out)
+
+	var parsed map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out), &parsed))
+	require.Equal(t, "Thing", parsed["kind"])
+	require.Equal(t, "webapp.example.com_v1_guestbook.yaml", FileName(&types.Type{GVK: &schema.GroupVersionKind{Group: "webapp.example.com", Version: "v1", Kind: "Guestbook"}}))
+}
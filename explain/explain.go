@@ -0,0 +1,305 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package explain describes Kinds and their fields in the style of kubectl explain.
+package explain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+)
+
+const (
+	indent         = "  "
+	resourceMarker = "kubebuilder:resource"
+)
+
+// Options configures how a Kind or field is explained.
+type Options struct {
+	// APIVersion restricts the lookup of the Kind to a group version such as "webapp.example.com/v1".
+	APIVersion string
+	// Recursive prints the whole tree of child fields instead of the direct children only.
+	Recursive bool
+	// MaxDepth limits the depth of the tree printed in recursive mode.
+	MaxDepth int
+}
+
+// Explain writes the description of the Kind or field identified by the query to w. The query is
+// a Kind (or its resource name) optionally followed by a dot-separated path of JSON field names,
+// for example "guestbook.spec.entries.rating".
+func Explain(w io.Writer, gvd []types.GroupVersionDetails, query string, opts Options) error {
+	parts := strings.Split(query, ".")
+	root, err := FindKind(gvd, parts[0], opts.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	t := root
+	var field *types.Field
+	for i, name := range parts[1:] {
+		field = findField(root, t, name)
+		if field == nil {
+			return fmt.Errorf("field %q does not exist in %s", name, strings.Join(parts[:i+1], "."))
+		}
+		t = field.Type
+	}
+
+	e := &explainer{w: w, opts: opts, documented: make(map[string]bool)}
+	for _, gv := range gvd {
+		for _, dt := range gv.Types {
+			e.documented[types.Key(dt)] = true
+		}
+	}
+
+	e.printf("GROUP:      %s\n", root.GVK.Group)
+	e.printf("KIND:       %s\n", root.GVK.Kind)
+	e.printf("VERSION:    %s\n\n", root.GVK.Version)
+
+	var doc string
+	var constraints []string
+	if field == nil {
+		doc = root.Doc
+	} else {
+		e.printf("FIELD: %s <%s>\n\n", field.Name, TypeName(t))
+		doc = field.Doc
+		if doc == "" && t != nil {
+			doc = t.Doc
+		}
+		constraints = append(constraints, field.Validation...)
+	}
+	if t != nil {
+		constraints = append(constraints, t.Validation...)
+	}
+
+	e.printf("DESCRIPTION:\n")
+	if doc == "" {
+		doc = "<empty>"
+	}
+	e.printText(doc, 2)
+
+	if len(constraints) > 0 {
+		e.printf("\nCONSTRAINTS:\n")
+		for _, c := range constraints {
+			e.printText(c, 2)
+		}
+	}
+
+	if field != nil && field.Default != nil {
+		e.printf("\nDEFAULT:\n")
+		e.printText(fmt.Sprintf("%v", field.Default), 2)
+	}
+
+	fields := members(root, t)
+	if len(fields) > 0 {
+		e.printf("\nFIELDS:\n")
+		if opts.Recursive {
+			e.printTree(fields, 1, map[string]bool{types.Key(t.ElemType()): true})
+		} else {
+			for _, f := range fields {
+				e.printf("%s%s\t<%s>\n", indent, f.Name, TypeName(f.Type))
+				if f.Doc != "" {
+					e.printText(f.Doc, 2)
+				}
+				e.printf("\n")
+			}
+		}
+	}
+
+	return e.err
+}
+
+// FindKind returns the root type of the Kind with the given name, resource name or short name.
+// The lookup is restricted to a group version if apiVersion is not empty.
+func FindKind(gvd []types.GroupVersionDetails, name, apiVersion string) (*types.Type, error) {
+	var matches []*types.Type
+	for _, gv := range gvd {
+		if apiVersion != "" && gv.GroupVersionString() != apiVersion {
+			continue
+		}
+
+		for _, k := range gv.SortedKinds() {
+			t := gv.TypeForKind(k)
+			if t != nil && t.GVK != nil && matchesKind(t, name) {
+				matches = append(matches, t)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("kind %q not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		var versions []string
+		for _, m := range matches {
+			versions = append(versions, m.GVK.GroupVersion().String())
+		}
+		return nil, fmt.Errorf("kind %q is defined in several API versions, select one of %s", name, strings.Join(versions, ", "))
+	}
+}
+
+func matchesKind(t *types.Type, name string) bool {
+	if strings.EqualFold(t.GVK.Kind, name) {
+		return true
+	}
+
+	if r, ok := t.Markers.Get(resourceMarker).(crdmarkers.Resource); ok {
+		if strings.EqualFold(r.Path, name) {
+			return true
+		}
+		for _, s := range r.ShortName {
+			if strings.EqualFold(s, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TypeName returns the name of a type as displayed next to a field.
+func TypeName(t *types.Type) string {
+	if t == nil {
+		return "<unknown>"
+	}
+
+	switch t.Kind {
+	case types.BasicKind:
+		switch t.Name {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+			return "integer"
+		case "float32", "float64":
+			return "number"
+		case "bool":
+			return "boolean"
+		default:
+			return t.Name
+		}
+	case types.PointerKind:
+		return TypeName(t.UnderlyingType)
+	case types.ArrayKind, types.SliceKind:
+		return "[]" + TypeName(t.UnderlyingType)
+	case types.MapKind:
+		return fmt.Sprintf("map[%s]%s", TypeName(t.KeyType), TypeName(t.ValueType))
+	case types.InterfaceKind:
+		return "Object"
+	default:
+		return t.Name
+	}
+}
+
+// members returns the fields of the values of type t, including the apiVersion and kind fields
+// of root objects that are not part of the processed members.
+func members(root, t *types.Type) types.Fields {
+	elem := t.ElemType()
+	if elem == nil {
+		return nil
+	}
+
+	fields := elem.Members()
+	if elem != root || root.GVK == nil {
+		return fields
+	}
+
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	var meta types.Fields
+	if findMember(fields, "apiVersion") == nil {
+		meta = append(meta, &types.Field{Name: "apiVersion", Type: str, Doc: fmt.Sprintf("%s/%s", root.GVK.Group, root.GVK.Version)})
+	}
+	if findMember(fields, "kind") == nil {
+		meta = append(meta, &types.Field{Name: "kind", Type: str, Doc: root.GVK.Kind})
+	}
+
+	return append(meta, fields...)
+}
+
+func findField(root, t *types.Type, name string) *types.Field {
+	fields := members(root, t)
+	if f := findMember(fields, name); f != nil {
+		return f
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func findMember(fields types.Fields, name string) *types.Field {
+	for _, f := range fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+type explainer struct {
+	w    io.Writer
+	opts Options
+	err  error
+	// documented holds the keys of the types declared by the processed API packages
+	documented map[string]bool
+}
+
+func (e *explainer) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = fmt.Fprintf(e.w, format, args...)
+}
+
+func (e *explainer) printText(text string, level int) {
+	prefix := strings.Repeat(indent, level)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line == "" {
+			e.printf("\n")
+			continue
+		}
+		e.printf("%s%s\n", prefix, line)
+	}
+}
+
+// printTree prints the names and types of the given fields and their children. Only types
+// declared by the processed API packages are expanded, unless they are already present on
+// the current path.
+func (e *explainer) printTree(fields types.Fields, level int, seen map[string]bool) {
+	for _, f := range fields {
+		e.printf("%s%s\t<%s>\n", strings.Repeat(indent, level), f.Name, TypeName(f.Type))
+
+		elem := f.Type.ElemType()
+		if elem == nil || level >= e.opts.MaxDepth {
+			continue
+		}
+
+		key := types.Key(elem)
+		if seen[key] || !e.documented[key] {
+			continue
+		}
+		seen[key] = true
+		e.printTree(elem.Members(), level+1, seen)
+		delete(seen, key)
+	}
+}
@@ -0,0 +1,88 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package explain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testGroupVersions() []types.GroupVersionDetails {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	entry := &types.Type{Name: "Entry", Package: "example.com/api/v1", Kind: types.StructKind, Doc: "Entry is an entry."}
+	entry.Fields = types.Fields{
+		{Name: "name", Type: str, Doc: "Name of the entry.", Validation: []string{"MaxLength: 10"}},
+	}
+	spec := &types.Type{Name: "BookSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "entries", Type: &types.Type{Name: "Entry", Package: "example.com/api/v1", Kind: types.SliceKind, UnderlyingType: entry}},
+	}
+	book := &types.Type{
+		Name:    "Book",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Doc:     "Book is a book.",
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Book"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}},
+	}
+
+	return []types.GroupVersionDetails{
+		{
+			GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+			Kinds:        []string{"Book"},
+			Types:        types.TypeMap{"Book": book, "BookSpec": spec, "Entry": entry},
+		},
+	}
+}
+
+func TestExplain(t *testing.T) {
+	gvd := testGroupVersions()
+
+	t.Run("kind", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Explain(&out, gvd, "book", Options{}))
+		require.Contains(t, out.String(), "KIND:       Book\n")
+		require.Contains(t, out.String(), "  apiVersion\t<string>\n    example.com/v1\n")
+		require.Contains(t, out.String(), "  spec\t<BookSpec>\n")
+	})
+
+	t.Run("field", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Explain(&out, gvd, "Book.spec.entries.name", Options{}))
+		require.Contains(t, out.String(), "FIELD: name <string>\n")
+		require.Contains(t, out.String(), "DESCRIPTION:\n    Name of the entry.\n")
+		require.Contains(t, out.String(), "CONSTRAINTS:\n    MaxLength: 10\n")
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Explain(&out, gvd, "book", Options{Recursive: true, MaxDepth: 5}))
+		require.Contains(t, out.String(), "  spec\t<BookSpec>\n    entries\t<[]Entry>\n      name\t<string>\n")
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		require.EqualError(t, Explain(&bytes.Buffer{}, gvd, "book.spec.pages", Options{}), `field "pages" does not exist in book.spec`)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		require.EqualError(t, Explain(&bytes.Buffer{}, gvd, "book", Options{APIVersion: "example.com/v2"}), `kind "book" not found`)
+	})
+}
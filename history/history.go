@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+// Package history annotates the Kinds, types and fields of an API with the releases in which they
+// were introduced and deprecated.
+package history
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/elastic/crd-ref-docs/apidiff"
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/processor"
+	"github.com/elastic/crd-ref-docs/types"
+	"go.uber.org/zap"
+)
+
+const deprecatedVersionMarker = "kubebuilder:deprecatedversion"
+
+// deprecatedRegex matches the paragraph of a doc comment that marks the element as deprecated, as
+// in Go doc comments.
+var deprecatedRegex = regexp.MustCompile(`(?m)^\s*Deprecated:`)
+
+// Release is the API as it was in a release.
+type Release struct {
+	Name string
+	API  []types.GroupVersionDetails
+}
+
+// Load processes the source path at every release configured in conf, each checked out in a
+// temporary git worktree.
+func Load(conf *config.Config) ([]Release, error) {
+	releases := make([]Release, 0, len(conf.Processor.Releases))
+	for _, name := range conf.Processor.Releases {
+		zap.S().Infow("Processing release", "release", name)
+		api, err := loadRelease(conf, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process release %s: %w", name, err)
+		}
+		releases = append(releases, Release{Name: name, API: api})
+	}
+	return releases, nil
+}
+
+func loadRelease(conf *config.Config, name string) ([]types.GroupVersionDetails, error) {
+	paths, cleanup, err := apidiff.Worktree(conf.SourcePaths, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			zap.S().Warnw("Failed to remove worktree", "release", name, "error", err)
+		}
+	}()
+
+	c := *conf
+	c.SourcePaths = paths
+	// worktrees are temporary, and the overlay replaces files of the working tree
+	c.CacheDir = ""
+	c.Overlay = nil
+	c.Processor.SamplesPath = ""
+	c.Processor.Releases = nil
+	return processor.Process(&c)
+}
+
+// state is the state of an element in a release.
+type state int
+
+const (
+	absent state = iota
+	available
+	deprecated
+)
+
+// Annotate sets the history of the Kinds, types and fields of gvd from the given releases, oldest
+// first. gvd is the final state of the API, following the releases: an element is available since
+// the first release of the last run of releases containing it up to gvd, and deprecated since the
+// first release of that run in which it is deprecated, if it still is in gvd. Elements that are
+// not in the last release have no history.
+func Annotate(gvd []types.GroupVersionDetails, releases []Release) {
+	states := make([]map[string]state, 0, len(releases)+1)
+	for _, r := range releases {
+		states = append(states, statesOf(r.API))
+	}
+	states = append(states, statesOf(gvd))
+
+	history := func(key string) types.History {
+		current := len(states) - 1
+		first := current
+		for first > 0 && states[first-1][key] != absent {
+			first--
+		}
+		if first == current {
+			return types.History{}
+		}
+
+		h := types.History{Since: releases[first].Name}
+		if states[current][key] == deprecated {
+			for i := current - 1; i >= first && states[i][key] == deprecated; i-- {
+				h.Deprecated = releases[i].Name
+			}
+		}
+		return h
+	}
+
+	// Fields of inlined types are shared with the types they are inlined into: they are annotated
+	// once, from the type sorted first.
+	annotated := make(map[*types.Field]bool)
+	for _, gv := range gvd {
+		for _, t := range gv.SortedTypes() {
+			key := typeKey(gv, t)
+			t.History = history(key)
+			for _, f := range t.Fields {
+				if !annotated[f] {
+					annotated[f] = true
+					f.History = history(key + "." + f.Name)
+				}
+			}
+		}
+	}
+}
+
+// statesOf returns the states of the types and fields of api, by key.
+func statesOf(api []types.GroupVersionDetails) map[string]state {
+	states := make(map[string]state)
+	for _, gv := range api {
+		for _, t := range gv.Types {
+			key := typeKey(gv, t)
+			states[key] = stateOf(t.Doc, isDeprecatedType(t))
+			for _, f := range t.Fields {
+				states[key+"."+f.Name] = stateOf(f.Doc, false)
+			}
+		}
+	}
+	return states
+}
+
+func typeKey(gv types.GroupVersionDetails, t *types.Type) string {
+	return gv.GroupVersionString() + "/" + t.Name
+}
+
+func isDeprecatedType(t *types.Type) bool {
+	return t.GVK != nil && t.Markers.Get(deprecatedVersionMarker) != nil
+}
+
+func stateOf(doc string, deprecatedMarker bool) state {
+	if deprecatedMarker || deprecatedRegex.MatchString(doc) {
+		return deprecated
+	}
+	return available
+}
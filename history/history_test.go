@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package history
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testAPI(fields ...*types.Field) []types.GroupVersionDetails {
+	spec := &types.Type{Name: "ThingSpec", Kind: types.StructKind, Fields: fields}
+	return []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Types:        types.TypeMap{"ThingSpec": spec},
+	}}
+}
+
+func TestAnnotate(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	releases := []Release{
+		{Name: "v1.0.0", API: testAPI(&types.Field{Name: "a", Type: str})},
+		{Name: "v1.1.0", API: testAPI(&types.Field{Name: "a", Type: str, Doc: "A.\n\nDeprecated: use b."}, &types.Field{Name: "b", Type: str})},
+		{Name: "v1.2.0", API: testAPI(&types.Field{Name: "a", Type: str, Doc: "A.\n\nDeprecated: use b."}, &types.Field{Name: "b", Type: str})},
+	}
+
+	gvd := testAPI(
+		&types.Field{Name: "a", Type: str, Doc: "A.\n\nDeprecated: use b."},
+		&types.Field{Name: "b", Type: str, Doc: "B.\n\nDeprecated: use c."},
+		&types.Field{Name: "c", Type: str},
+	)
+	Annotate(gvd, releases)
+
+	spec := gvd[0].Types["ThingSpec"]
+	require.Equal(t, types.History{Since: "v1.0.0"}, spec.History)
+	require.Equal(t, types.History{Since: "v1.0.0", Deprecated: "v1.1.0"}, spec.Fields[0].History)
+	// deprecations that are not released yet are not recorded
+	require.Equal(t, types.History{Since: "v1.1.0"}, spec.Fields[1].History)
+	require.Equal(t, types.History{}, spec.Fields[2].History)
+
+	require.Equal(t, "Available since v1.0.0, deprecated since v1.1.0.", spec.Fields[0].Availability())
+	require.Empty(t, spec.Fields[2].Availability())
+}
+
+func TestAnnotateRemovedAndAdded(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	releases := []Release{
+		{Name: "v1.0.0", API: testAPI(&types.Field{Name: "a", Type: str, Doc: "A.\n\nDeprecated: unused."}, &types.Field{Name: "b", Type: str})},
+		{Name: "v1.1.0", API: testAPI(&types.Field{Name: "b", Type: str})},
+		{Name: "v1.2.0", API: testAPI(&types.Field{Name: "a", Type: str}, &types.Field{Name: "b", Type: str})},
+		{Name: "v1.3.0", API: testAPI(&types.Field{Name: "a", Type: str})},
+	}
+
+	gvd := testAPI(&types.Field{Name: "a", Type: str}, &types.Field{Name: "b", Type: str})
+	Annotate(gvd, releases)
+
+	spec := gvd[0].Types["ThingSpec"]
+	// a was removed in v1.1.0 and added again in v1.2.0
+	require.Equal(t, types.History{Since: "v1.2.0"}, spec.Fields[0].History)
+	// b was removed in v1.3.0 and added again after the last release
+	require.Equal(t, types.History{}, spec.Fields[1].History)
+}
@@ -0,0 +1,367 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package jsonschema builds JSON schemas describing the processed types.
+package jsonschema
+
+import (
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// Draft202012 is the URI of the JSON Schema draft 2020-12 meta-schema.
+const Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// Dialect is the flavour of JSON Schema to build.
+type Dialect int
+
+const (
+	// JSONSchema is JSON Schema draft 2020-12.
+	JSONSchema Dialect = iota
+	// OpenAPI is the subset of JSON Schema used by OpenAPI 3.0 documents, in which the siblings of
+	// $ref are ignored and exclusive bounds are flags on the minimum and maximum.
+	OpenAPI
+)
+
+// wellKnownSchemas holds the schemas of types whose Go representation differs from their
+// serialized form.
+var wellKnownSchemas = map[string]Schema{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                          {Type: "string", Format: "date-time"},
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                     {Type: "string", Format: "date-time"},
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                      {Type: "string"},
+	"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                    {Type: "object"},
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                      {AnyOf: []*Schema{{Type: "integer"}, {Type: "string"}}, XKubernetesIntOrString: true},
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                    {AnyOf: []*Schema{{Type: "integer"}, {Type: "string"}}, XKubernetesIntOrString: true},
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                       {Type: "object", XKubernetesPreserveUnknownFields: true},
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON":      {XKubernetesPreserveUnknownFields: true},
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1.JSON": {XKubernetesPreserveUnknownFields: true},
+}
+
+// Schema is a JSON schema, with the OpenAPI and Kubernetes extensions used to describe API types.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Nullable    bool   `json:"nullable,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum any      `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum any      `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	UniqueItems      bool     `json:"uniqueItems,omitempty"`
+	MinProperties    *int     `json:"minProperties,omitempty"`
+	MaxProperties    *int     `json:"maxProperties,omitempty"`
+
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	XKubernetesGroupVersionKind      []GroupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+	XKubernetesEmbeddedResource      bool               `json:"x-kubernetes-embedded-resource,omitempty"`
+	XKubernetesIntOrString           bool               `json:"x-kubernetes-int-or-string,omitempty"`
+	XKubernetesPreserveUnknownFields bool               `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XKubernetesValidations           []Validation       `json:"x-kubernetes-validations,omitempty"`
+}
+
+// GroupVersionKind identifies the Kind described by a schema.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Validation is a CEL validation rule.
+type Validation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message,omitempty"`
+}
+
+// Builder builds the schemas of types. The schemas of named types are added to the definitions of
+// the builder and referred to by $ref.
+type Builder struct {
+	dialect   Dialect
+	refPrefix string
+	// Defs holds the schemas of the named types referred to so far, by definition name.
+	Defs map[string]*Schema
+}
+
+// NewBuilder returns a builder for the given dialect, whose references are made of refPrefix
+// followed by the name of the definition.
+func NewBuilder(dialect Dialect, refPrefix string) *Builder {
+	return &Builder{dialect: dialect, refPrefix: refPrefix, Defs: make(map[string]*Schema)}
+}
+
+// DefinitionName returns the name of the definition of a named type, made of its package path with
+// the domain reversed, as in the OpenAPI documents served by Kubernetes.
+func DefinitionName(t *types.Type) string {
+	parts := strings.Split(t.Package, "/")
+	if domain := strings.Split(parts[0], "."); len(domain) > 1 {
+		for i, j := 0, len(domain)-1; i < j; i, j = i+1, j-1 {
+			domain[i], domain[j] = domain[j], domain[i]
+		}
+		parts[0] = strings.Join(domain, ".")
+	}
+
+	return strings.Join(append(parts, t.Name), ".")
+}
+
+// Kind returns the schema of the Kind described by the given root type.
+func (b *Builder) Kind(t *types.Type) *Schema {
+	s := b.describe(t)
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+
+	if t.GVK != nil {
+		s.Properties["apiVersion"] = &Schema{
+			Description: "APIVersion defines the versioned schema of this representation of an object.",
+			Type:        "string",
+			Enum:        []any{t.GVK.GroupVersion().String()},
+		}
+		s.Properties["kind"] = &Schema{
+			Description: "Kind is a string value representing the REST resource this object represents.",
+			Type:        "string",
+			Enum:        []any{t.GVK.Kind},
+		}
+		s.XKubernetesGroupVersionKind = []GroupVersionKind{{Group: t.GVK.Group, Version: t.GVK.Version, Kind: t.GVK.Kind}}
+	}
+
+	return s
+}
+
+// Define adds the schema of the given named type to the definitions, unless it is already
+// present, and returns the name of the definition.
+func (b *Builder) Define(t *types.Type) string {
+	name := DefinitionName(t)
+	if _, ok := b.Defs[name]; !ok {
+		// reserve the name first as the type may refer to itself
+		b.Defs[name] = nil
+		b.Defs[name] = b.describe(t)
+	}
+	return name
+}
+
+// Schema returns the schema of a value of the given type.
+func (b *Builder) Schema(t *types.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if s, ok := wellKnownSchemas[types.Key(t)]; ok {
+		return &s
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		return b.Schema(t.UnderlyingType)
+
+	case types.SliceKind, types.ArrayKind:
+		if e := t.UnderlyingType; e != nil && e.Kind == types.BasicKind && (e.Name == "byte" || e.Name == "uint8") {
+			// byte slices are serialized as base64 strings
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.Schema(t.UnderlyingType)}
+
+	case types.MapKind:
+		return &Schema{Type: "object", AdditionalProperties: b.Schema(t.ValueType)}
+
+	case types.BasicKind:
+		return basicSchema(t.Name)
+
+	case types.AliasKind, types.StructKind:
+		if t.Name == "" || t.Package == "" {
+			return b.describe(t)
+		}
+		return &Schema{Ref: b.refPrefix + b.Define(t)}
+
+	default:
+		return &Schema{XKubernetesPreserveUnknownFields: true}
+	}
+}
+
+// describe returns the schema of the given struct or alias type itself, rather than a reference to it.
+func (b *Builder) describe(t *types.Type) *Schema {
+	var s *Schema
+	if t.Kind == types.StructKind {
+		s = &Schema{Type: "object"}
+		for _, f := range t.Members() {
+			if f.Name == "" {
+				continue
+			}
+			if s.Properties == nil {
+				s.Properties = make(map[string]*Schema)
+			}
+			s.Properties[f.Name] = b.field(f)
+			if !f.Optional {
+				s.Required = append(s.Required, f.Name)
+			}
+		}
+	} else {
+		s = b.Schema(t.UnderlyingType)
+	}
+
+	s.Description = t.Doc
+	b.applyMarkers(s, t.Markers)
+	return s
+}
+
+func (b *Builder) field(f *types.Field) *Schema {
+	s := b.Schema(f.Type)
+	if f.Doc == "" && f.Default == nil && len(f.Markers) == 0 {
+		return s
+	}
+
+	if s.Ref != "" && b.dialect == OpenAPI {
+		s = &Schema{AllOf: []*Schema{s}}
+	}
+
+	s.Description = f.Doc
+	s.Default = f.Default
+	b.applyMarkers(s, f.Markers)
+	return s
+}
+
+func basicSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32", "byte", "rune":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int", "int64", "uint", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+// applyMarkers sets the constraints and extensions declared by validation markers.
+func (b *Builder) applyMarkers(s *Schema, values markers.MarkerValues) {
+	if len(values) == 0 {
+		return
+	}
+
+	if v, ok := values.Get(types.TypeMarker).(crdmarkers.Type); ok {
+		s.Type = string(v)
+	}
+	if v, ok := values.Get(types.FormatMarker).(crdmarkers.Format); ok {
+		s.Format = string(v)
+	}
+	if v, ok := values.Get(types.EnumMarker).(crdmarkers.Enum); ok {
+		s.Enum = v
+	}
+	if v, ok := values.Get(types.MinimumMarker).(crdmarkers.Minimum); ok {
+		s.Minimum = ptr(float64(v))
+	}
+	if v, ok := values.Get(types.MaximumMarker).(crdmarkers.Maximum); ok {
+		s.Maximum = ptr(float64(v))
+	}
+	if v, ok := values.Get(types.MultipleOfMarker).(crdmarkers.MultipleOf); ok {
+		s.MultipleOf = ptr(float64(v))
+	}
+	if v, ok := values.Get(types.MinLengthMarker).(crdmarkers.MinLength); ok {
+		s.MinLength = ptr(int(v))
+	}
+	if v, ok := values.Get(types.MaxLengthMarker).(crdmarkers.MaxLength); ok {
+		s.MaxLength = ptr(int(v))
+	}
+	if v, ok := values.Get(types.PatternMarker).(crdmarkers.Pattern); ok {
+		s.Pattern = string(v)
+	}
+	if v, ok := values.Get(types.MinItemsMarker).(crdmarkers.MinItems); ok {
+		s.MinItems = ptr(int(v))
+	}
+	if v, ok := values.Get(types.MaxItemsMarker).(crdmarkers.MaxItems); ok {
+		s.MaxItems = ptr(int(v))
+	}
+	if v, ok := values.Get(types.UniqueItemsMarker).(crdmarkers.UniqueItems); ok {
+		s.UniqueItems = bool(v)
+	}
+	if v, ok := values.Get(types.MinPropertiesMarker).(crdmarkers.MinProperties); ok {
+		s.MinProperties = ptr(int(v))
+	}
+	if v, ok := values.Get(types.MaxPropertiesMarker).(crdmarkers.MaxProperties); ok {
+		s.MaxProperties = ptr(int(v))
+	}
+	if _, ok := values.Get("nullable").(crdmarkers.Nullable); ok && b.dialect == OpenAPI {
+		s.Nullable = true
+	}
+	if values.Get(types.XIntOrStringMarker) != nil {
+		s.XKubernetesIntOrString = true
+	}
+	if values.Get(types.XPreserveUnknownFieldsMarker) != nil || values.Get(types.PreserveUnknownFieldsMarker) != nil {
+		s.XKubernetesPreserveUnknownFields = true
+	}
+	if values.Get(types.XEmbeddedResourceMarker) != nil || values.Get(types.EmbeddedResourceMarker) != nil {
+		s.XKubernetesEmbeddedResource = true
+	}
+	for _, v := range values[types.XValidationMarker] {
+		if rule, ok := v.(crdmarkers.XValidation); ok {
+			s.XKubernetesValidations = append(s.XKubernetesValidations, Validation{Rule: rule.Rule, Message: rule.Message})
+		}
+	}
+
+	b.applyExclusiveBounds(s, values)
+}
+
+// applyExclusiveBounds sets the exclusive bounds, which are flags in OpenAPI 3.0 but replace the
+// inclusive bounds in JSON Schema.
+func (b *Builder) applyExclusiveBounds(s *Schema, values markers.MarkerValues) {
+	if v, ok := values.Get(types.ExclusiveMinimumMarker).(crdmarkers.ExclusiveMinimum); ok && bool(v) {
+		if b.dialect == OpenAPI {
+			s.ExclusiveMinimum = true
+		} else if s.Minimum != nil {
+			s.ExclusiveMinimum = *s.Minimum
+			s.Minimum = nil
+		}
+	}
+	if v, ok := values.Get(types.ExclusiveMaximumMarker).(crdmarkers.ExclusiveMaximum); ok && bool(v) {
+		if b.dialect == OpenAPI {
+			s.ExclusiveMaximum = true
+		} else if s.Maximum != nil {
+			s.ExclusiveMaximum = *s.Maximum
+			s.Maximum = nil
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func testKind() *types.Type {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	integer := &types.Type{Name: "int32", Kind: types.BasicKind}
+	level := &types.Type{
+		Name:           "Level",
+		Package:        "example.com/api/v1",
+		Doc:            "Level of the thing.",
+		Kind:           types.AliasKind,
+		UnderlyingType: str,
+		Markers:        markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum{"low", "high"}}},
+	}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "replicas", Type: integer, Default: 3, Optional: true, Markers: markers.MarkerValues{
+			types.MinimumMarker:          {crdmarkers.Minimum(0)},
+			types.ExclusiveMinimumMarker: {crdmarkers.ExclusiveMinimum(true)},
+		}},
+		{Name: "level", Type: level, Doc: "Level to use."},
+		{Name: "parent", Type: &types.Type{Kind: types.PointerKind, UnderlyingType: spec}, Optional: true},
+		{Name: "data", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Name: "byte", Kind: types.BasicKind}}},
+	}
+	return &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}},
+	}
+}
+
+func TestBuilderJSONSchema(t *testing.T) {
+	b := NewBuilder(JSONSchema, "#/$defs/")
+	s := b.Kind(testKind())
+	s.Defs = b.Defs
+
+	out, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"apiVersion": {"description": "APIVersion defines the versioned schema of this representation of an object.", "type": "string", "enum": ["example.com/v1"]},
+			"kind": {"description": "Kind is a string value representing the REST resource this object represents.", "type": "string", "enum": ["Thing"]},
+			"spec": {"$ref": "#/$defs/com.example.api.v1.ThingSpec"}
+		},
+		"required": ["spec"],
+		"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Thing"}],
+		"$defs": {
+			"com.example.api.v1.Level": {"description": "Level of the thing.", "type": "string", "enum": ["low", "high"]},
+			"com.example.api.v1.ThingSpec": {
+				"type": "object",
+				"properties": {
+					"replicas": {"type": "integer", "format": "int32", "default": 3, "exclusiveMinimum": 0},
+					"level": {"$ref": "#/$defs/com.example.api.v1.Level", "description": "Level to use."},
+					"parent": {"$ref": "#/$defs/com.example.api.v1.ThingSpec"},
+					"data": {"type": "string", "format": "byte"}
+				},
+				"required": ["level", "data"]
+			}
+		}
+	}`, string(out))
+}
+
+func TestBuilderOpenAPI(t *testing.T) {
+	b := NewBuilder(OpenAPI, "#/components/schemas/")
+	b.Define(testKind())
+
+	spec := b.Defs["com.example.api.v1.ThingSpec"]
+	require.NotNil(t, spec)
+
+	out, err := json.Marshal(spec.Properties)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"replicas": {"type": "integer", "format": "int32", "default": 3, "minimum": 0, "exclusiveMinimum": true},
+		"level": {"allOf": [{"$ref": "#/components/schemas/com.example.api.v1.Level"}], "description": "Level to use."},
+		"parent": {"$ref": "#/components/schemas/com.example.api.v1.ThingSpec"},
+		"data": {"type": "string", "format": "byte"}
+	}`, string(out))
+	require.Equal(t, []string{"level", "data"}, spec.Required)
+}
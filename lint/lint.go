@@ -0,0 +1,208 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+// Package lint reports missing and poor documentation of API types and fields.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// metadataField is the standard field of Kinds that is documented by Kubernetes.
+const metadataField = "metadata"
+
+var (
+	// placeholderRegex matches placeholder text, including the comments scaffolded by kubebuilder.
+	placeholderRegex = regexp.MustCompile(`\b(TODO|FIXME|TBD|XXX)\b|INSERT ADDITIONAL|Edit \S+_types\.go to remove/update`)
+
+	// fillerWords are the words that do not describe anything on their own.
+	fillerWords = map[string]bool{"a": true, "an": true, "the": true, "is": true, "of": true, "for": true}
+)
+
+// Problem is a documentation problem of a type or field.
+type Problem struct {
+	GroupVersion string
+	// Name is the name of the type, or the path of the field within its type such as
+	// "GuestbookSpec.page". It is empty for problems of the group version itself.
+	Name    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Name == "" {
+		return fmt.Sprintf("%s: %s", p.GroupVersion, p.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", p.GroupVersion, p.Name, p.Message)
+}
+
+// Coverage counts the documented types and fields of a group version.
+type Coverage struct {
+	GroupVersion string
+	Documented   int
+	Total        int
+}
+
+// Percent returns the percentage of documented types and fields.
+func (c Coverage) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return 100 * float64(c.Documented) / float64(c.Total)
+}
+
+func (c Coverage) String() string {
+	return fmt.Sprintf("%s: %.1f%% documented (%d/%d)", c.GroupVersion, c.Percent(), c.Documented, c.Total)
+}
+
+// Report lists the documentation problems and the coverage of every group version.
+type Report struct {
+	Problems []Problem
+	Coverage []Coverage
+}
+
+// Lint checks the documentation of the types and fields of the given group versions.
+func Lint(gvd []types.GroupVersionDetails) *Report {
+	r := &Report{}
+	for _, gv := range gvd {
+		l := &linter{report: r, gv: gv.GroupVersionString(), seen: make(map[*types.Field]bool)}
+		if len(gv.Kinds) > 0 && strings.TrimSpace(gv.Doc) == "" {
+			l.problem("", "missing package documentation")
+		}
+
+		cov := Coverage{GroupVersion: l.gv}
+		for _, t := range gv.SortedTypes() {
+			l.lintType(t, &cov)
+		}
+		r.Coverage = append(r.Coverage, cov)
+	}
+	return r
+}
+
+type linter struct {
+	report *Report
+	gv     string
+	// seen holds the fields already checked, as the fields of inlined types are shared with the
+	// types they are inlined into.
+	seen map[*types.Field]bool
+}
+
+func (l *linter) problem(name, format string, args ...any) {
+	l.report.Problems = append(l.report.Problems, Problem{GroupVersion: l.gv, Name: name, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) lintType(t *types.Type, cov *Coverage) {
+	cov.Total++
+	if l.lintDoc(t.Name, t.Doc, []string{t.Name}, nil, true) {
+		cov.Documented++
+	}
+
+	for _, f := range t.Fields {
+		if l.seen[f] || f.Name == metadataField || f.Inlined {
+			continue
+		}
+		l.seen[f] = true
+
+		names := []string{f.Name}
+		if f.GoName != "" && f.GoName != f.Name {
+			names = append(names, f.GoName)
+		}
+
+		cov.Total++
+		if l.lintDoc(t.Name+"."+f.Name, f.Doc, names, f.Type.ElemType(), false) {
+			cov.Documented++
+		}
+	}
+}
+
+// lintDoc checks the documentation of the element with the given names, and reports whether it
+// is documented. The documentation of types may start with an article, as in "A Guestbook is...".
+func (l *linter) lintDoc(path, doc string, names []string, t *types.Type, article bool) bool {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		l.problem(path, "missing documentation")
+		return false
+	}
+
+	if placeholderRegex.MatchString(doc) {
+		l.problem(path, "documentation contains placeholder text")
+	}
+
+	words := strings.FieldsFunc(doc, func(r rune) bool { return !isWordRune(r) })
+	if !startsWithName(words, names, article) {
+		l.problem(path, "documentation should start with %s", quoteNames(names))
+	}
+
+	repeated := append([]string(nil), names...)
+	if t != nil && t.Name != "" {
+		repeated = append(repeated, t.Name)
+	}
+	if onlyRepeats(words, repeated) {
+		l.problem(path, "documentation only repeats the name of the element or its type")
+	}
+
+	return true
+}
+
+// startsWithName reports whether the words start with one of the names, optionally preceded by
+// an article.
+func startsWithName(words, names []string, article bool) bool {
+	if len(words) == 0 {
+		return false
+	}
+	candidates := words[:1]
+	if article && len(words) > 1 && (words[0] == "A" || words[0] == "An" || words[0] == "The") {
+		candidates = words[:2]
+	}
+	for _, w := range candidates {
+		for _, n := range names {
+			if w == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// onlyRepeats reports whether the words are only made of the given names and filler words.
+func onlyRepeats(words, names []string) bool {
+	for _, w := range words {
+		repeated := fillerWords[strings.ToLower(w)]
+		for _, n := range names {
+			repeated = repeated || strings.EqualFold(w, n)
+		}
+		if !repeated {
+			return false
+		}
+	}
+	return true
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, " or ")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package lint
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLint(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	spec := &types.Type{Name: "ThingSpec", Kind: types.StructKind, Doc: "ThingSpec defines the desired state of Thing."}
+	spec.Fields = types.Fields{
+		{Name: "name", GoName: "Name", Type: str, Doc: "Name of the thing."},
+		{Name: "size", GoName: "Size", Type: str, Doc: "size of the thing."},
+		{Name: "foo", GoName: "Foo", Type: str, Doc: "Foo is an example field of Thing. Edit thing_types.go to remove/update"},
+		{Name: "color", GoName: "Color", Type: str, Doc: "The color to paint it."},
+		{Name: "mode", GoName: "Mode", Type: &types.Type{Name: "Mode", Kind: types.AliasKind, UnderlyingType: str}, Doc: "Mode is the mode."},
+		{Name: "extra", GoName: "Extra", Type: str},
+	}
+	thing := &types.Type{
+		Name:   "Thing",
+		Kind:   types.StructKind,
+		GVK:    &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Doc:    "A Thing is a thing.",
+		Fields: types.Fields{{Name: "metadata", Type: str}, {Name: "spec", GoName: "Spec", Type: spec}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec},
+	}}
+
+	report := Lint(gvd)
+	var problems []string
+	for _, p := range report.Problems {
+		problems = append(problems, p.String())
+	}
+	require.Equal(t, []string{
+		"example.com/v1: missing package documentation",
+		"example.com/v1 Thing: documentation only repeats the name of the element or its type",
+		"example.com/v1 Thing.spec: missing documentation",
+		"example.com/v1 ThingSpec.foo: documentation contains placeholder text",
+		"example.com/v1 ThingSpec.color: documentation should start with \"color\" or \"Color\"",
+		"example.com/v1 ThingSpec.mode: documentation only repeats the name of the element or its type",
+		"example.com/v1 ThingSpec.extra: missing documentation",
+	}, problems)
+
+	require.Len(t, report.Coverage, 1)
+	require.Equal(t, Coverage{GroupVersion: "example.com/v1", Documented: 7, Total: 9}, report.Coverage[0])
+	require.Equal(t, "example.com/v1: 77.8% documented (7/9)", report.Coverage[0].String())
+}
@@ -17,19 +17,39 @@
 package main
 
 import (
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/elastic/crd-ref-docs/apidiff"
 	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/example"
+	"github.com/elastic/crd-ref-docs/explain"
+	"github.com/elastic/crd-ref-docs/history"
+	"github.com/elastic/crd-ref-docs/lint"
+	"github.com/elastic/crd-ref-docs/manifest"
 	"github.com/elastic/crd-ref-docs/processor"
 	"github.com/elastic/crd-ref-docs/renderer"
+	"github.com/elastic/crd-ref-docs/serve"
+	"github.com/elastic/crd-ref-docs/types"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var args = config.Flags{}
+var (
+	args        = config.Flags{}
+	explainOpts = explain.Options{}
+	diffFormat  string
+	diffGit     bool
+	serveOpts   = struct {
+		Addr         string
+		Renderer     string
+		TemplatesDir string
+	}{}
+)
 
 func main() {
 	cmd := cobra.Command{
@@ -39,15 +59,91 @@ func main() {
 		RunE:         doRun,
 	}
 
-	cmd.Flags().StringVar(&args.LogLevel, "log-level", "INFO", "Log level")
-	cmd.Flags().StringVar(&args.Config, "config", "config.yaml", "Path to config file")
-	cmd.Flags().StringVar(&args.SourcePath, "source-path", "", "Path to source directory containing CRDs")
+	cmd.PersistentFlags().StringVar(&args.LogLevel, "log-level", "INFO", "Log level")
+	cmd.PersistentFlags().StringVar(&args.Config, "config", "config.yaml", "Path to config file")
+	cmd.PersistentFlags().StringArrayVar(&args.SourcePaths, "source-path", nil, "Path to source directory containing CRDs, may be repeated")
+	cmd.PersistentFlags().IntVar(&args.MaxDepth, "max-depth", 6, "Maximum recursion level for type discovery")
+	cmd.PersistentFlags().StringVar(&args.Overlay, "overlay", "", "Path to a JSON file replacing source files, in the format of the -overlay flag of go build")
+	cmd.PersistentFlags().StringVar(&args.CacheDir, "cache-dir", "", "Path to a directory to cache the processed types in, skipping processing when the source paths did not change")
 	cmd.Flags().StringVar(&args.TemplatesDir, "templates-dir", "", "Path to the directory containing template files")
-	cmd.Flags().StringVar(&args.Renderer, "renderer", "asciidoctor", "Renderer to use ('asciidoctor' or 'markdown')")
+	cmd.Flags().StringVar(&args.Renderer, "renderer", "asciidoctor", "Renderer to use ('asciidoctor', 'markdown', 'html', 'rst', 'jsonschema', 'openapi', 'typescript', 'cue', 'mermaid' or 'dot')")
 	cmd.Flags().StringVar(&args.OutputPath, "output-path", ".", "Path to output the rendered result")
-	cmd.Flags().IntVar(&args.MaxDepth, "max-depth", 6, "Maximum recursion level for type discovery")
+	cmd.Flags().BoolVar(&args.Check, "check", false, "Check that the files at the output path are up to date instead of writing them, printing the differences")
+	cmd.Flags().StringVar(&args.ExamplesDir, "examples-dir", "", "Path to a directory to write an example manifest for every Kind into")
+
+	explainCmd := &cobra.Command{
+		Use:          "explain KIND[.FIELD]...",
+		Short:        "Describe a Kind or one of its fields",
+		Example:      "  crd-ref-docs explain guestbook.spec.entries.rating --source-path=./api",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         doExplain,
+	}
+	explainCmd.Flags().StringVar(&explainOpts.APIVersion, "api-version", "", "Group version of the Kind to explain, when it is defined in several versions")
+	explainCmd.Flags().BoolVar(&explainOpts.Recursive, "recursive", false, "Print the fields of all nested types")
+	cmd.AddCommand(explainCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "validate PATH...",
+		Short:        "Validate manifests against the API types",
+		Example:      "  crd-ref-docs validate config/samples --source-path=./api",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         doValidate,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:          "lint",
+		Short:        "Report missing and poor documentation of types and fields",
+		Example:      "  crd-ref-docs lint --source-path=./api",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         doLint,
+	})
 
-	cmd.Execute()
+	serveCmd := &cobra.Command{
+		Use:          "serve",
+		Short:        "Preview the documentation in a browser, rebuilding it when the sources or templates change",
+		Example:      "  crd-ref-docs serve --source-path=./api --renderer=markdown --templates-dir=templates/markdown",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         doServe,
+	}
+	serveCmd.Flags().StringVar(&serveOpts.Addr, "addr", "localhost:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveOpts.Renderer, "renderer", "html", "Renderer to use ('html' or 'markdown')")
+	serveCmd.Flags().StringVar(&serveOpts.TemplatesDir, "templates-dir", "", "Path to the directory containing template files")
+	cmd.AddCommand(serveCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff OLD [NEW]",
+		Short: "Summarise the API changes between two source trees or git revisions",
+		Example: "  crd-ref-docs diff old/api new/api\n" +
+			"  crd-ref-docs diff --git v1.0.0 HEAD --source-path=./api\n" +
+			"  crd-ref-docs diff --git origin/main --source-path=./api",
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE:         doDiff,
+	}
+	diffCmd.Flags().StringVar(&diffFormat, "format", "markdown", "Output format ('markdown' or 'json')")
+	diffCmd.Flags().BoolVar(&diffGit, "git", false, "Compare git revisions of the repository containing the source path instead of two source paths. "+
+		"The working tree is compared to OLD when NEW is omitted")
+	cmd.AddCommand(diffCmd)
+
+	compatCmd := &cobra.Command{
+		Use:   "check-compat OLD [NEW]",
+		Short: "Fail on breaking changes to served versions between two source trees or git revisions",
+		Example: "  crd-ref-docs check-compat old/api new/api\n" +
+			"  crd-ref-docs check-compat --git origin/main --source-path=./api",
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE:         doCheckCompat,
+	}
+	compatCmd.Flags().AddFlagSet(diffCmd.Flags())
+	cmd.AddCommand(compatCmd)
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
 
 func doRun(_ *cobra.Command, _ []string) error {
@@ -71,23 +167,337 @@ func doRun(_ *cobra.Command, _ []string) error {
 		zap.S().Infof("Execution time: %s", time.Since(startTime))
 	}()
 
-	zap.S().Infow("Processing source directory", "directory", conf.SourcePath, "depth", conf.MaxDepth)
-	gvd, err := processor.Process(conf)
+	gvd, err := process(conf)
 	if err != nil {
-		zap.S().Errorw("Failed to process source directory", "error", err)
 		return err
 	}
 
+	if len(conf.Processor.Releases) > 0 {
+		releases, err := history.Load(conf)
+		if err != nil {
+			zap.S().Errorw("Failed to process releases", "error", err)
+			return err
+		}
+		history.Annotate(gvd, releases)
+	}
+
+	if conf.Check {
+		return check(r, gvd, conf.OutputPath)
+	}
+
 	zap.S().Infow("Rendering output", "path", conf.OutputPath)
 	if err := r.Render(gvd); err != nil {
 		zap.S().Errorw("Failed to render", "error", err)
 		return err
 	}
 
+	if conf.ExamplesDir != "" {
+		zap.S().Infow("Writing example manifests", "path", conf.ExamplesDir)
+		if err := example.WriteFiles(conf.ExamplesDir, gvd, conf.MaxDepth); err != nil {
+			zap.S().Errorw("Failed to write example manifests", "error", err)
+			return err
+		}
+	}
+
 	zap.S().Info("CRD reference documentation generated")
 	return nil
 }
 
+func check(r renderer.Renderer, gvd []types.GroupVersionDetails, outputPath string) error {
+	zap.S().Infow("Checking output", "path", outputPath)
+	diff, err := renderer.Check(r, gvd, outputPath)
+	if err != nil {
+		zap.S().Errorw("Failed to render", "error", err)
+		return err
+	}
+
+	if diff != "" {
+		fmt.Print(diff)
+		return fmt.Errorf("the files at %s are out of date", outputPath)
+	}
+
+	zap.S().Info("CRD reference documentation is up to date")
+	return nil
+}
+
+func doExplain(cmd *cobra.Command, cmdArgs []string) error {
+	initCommandLogging(cmd)
+
+	conf, err := config.Load(args)
+	if err != nil {
+		zap.S().Errorw("Failed to read config", "error", err)
+		return err
+	}
+
+	gvd, err := process(conf)
+	if err != nil {
+		return err
+	}
+
+	explainOpts.MaxDepth = conf.MaxDepth
+	return explain.Explain(cmd.OutOrStdout(), gvd, cmdArgs[0], explainOpts)
+}
+
+func doValidate(cmd *cobra.Command, cmdArgs []string) error {
+	initCommandLogging(cmd)
+
+	conf, err := config.Load(args)
+	if err != nil {
+		zap.S().Errorw("Failed to read config", "error", err)
+		return err
+	}
+
+	gvd, err := process(conf)
+	if err != nil {
+		return err
+	}
+	ignored, err := processor.IgnoredField(conf)
+	if err != nil {
+		return err
+	}
+
+	var problems int
+	for _, path := range cmdArgs {
+		docs, err := manifest.Load(path)
+		if err != nil {
+			zap.S().Errorw("Failed to load manifests", "path", path, "error", err)
+			return err
+		}
+
+		for _, doc := range docs {
+			for _, p := range manifest.Validate(gvd, doc, ignored) {
+				fmt.Fprintln(cmd.OutOrStdout(), p)
+				if !p.Warning {
+					problems++
+				}
+			}
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problem(s)", problems)
+	}
+	return nil
+}
+
+func doLint(cmd *cobra.Command, _ []string) error {
+	initCommandLogging(cmd)
+
+	conf, err := config.Load(args)
+	if err != nil {
+		zap.S().Errorw("Failed to read config", "error", err)
+		return err
+	}
+
+	gvd, err := process(conf)
+	if err != nil {
+		return err
+	}
+
+	report := lint.Lint(gvd)
+	for _, p := range report.Problems {
+		fmt.Fprintln(cmd.OutOrStdout(), p)
+	}
+	if len(report.Problems) > 0 {
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
+
+	var below int
+	for _, c := range report.Coverage {
+		fmt.Fprintln(cmd.OutOrStdout(), c)
+		if c.Percent() < conf.Lint.MinCoverage {
+			below++
+		}
+	}
+
+	if below > 0 {
+		return fmt.Errorf("documentation coverage of %d group version(s) is below %.1f%%", below, conf.Lint.MinCoverage)
+	}
+	return nil
+}
+
+func doServe(_ *cobra.Command, _ []string) error {
+	initLogging(args.LogLevel)
+
+	if serveOpts.Renderer != "html" && serveOpts.Renderer != "markdown" {
+		return fmt.Errorf("unsupported renderer %q", serveOpts.Renderer)
+	}
+	args.Renderer = serveOpts.Renderer
+	args.TemplatesDir = serveOpts.TemplatesDir
+	// The output is kept in memory: the root directory only gives the rendered files their paths.
+	args.OutputPath = "/"
+
+	server := serve.New(func() (map[string][]byte, error) {
+		conf, err := config.Load(args)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := renderer.New(conf)
+		if err != nil {
+			return nil, err
+		}
+
+		gvd, err := processor.Process(conf)
+		if err != nil {
+			return nil, err
+		}
+
+		out := renderer.NewMemoryOutput()
+		if err := renderer.RenderTo(r, gvd, out); err != nil {
+			return nil, err
+		}
+
+		zap.S().Info("Documentation rebuilt")
+		return out.Files, nil
+	})
+	server.Rebuild()
+
+	watched := []string{args.Config}
+	if conf, err := config.Load(args); err == nil {
+		// the source paths and samples may be set by the configuration file
+		watched = append(watched, conf.SourcePaths...)
+		if conf.Processor.SamplesPath != "" {
+			watched = append(watched, conf.Processor.SamplesPath)
+		}
+	} else {
+		watched = append(watched, args.SourcePaths...)
+	}
+	if args.Overlay != "" {
+		watched = append(watched, args.Overlay)
+	}
+	if args.TemplatesDir != "" {
+		watched = append(watched, args.TemplatesDir)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		if err := serve.Watch(watched, server.Rebuild, done); err != nil {
+			zap.S().Errorw("Failed to watch files", "error", err)
+		}
+	}()
+
+	zap.S().Infow("Serving documentation", "url", "http://"+serveOpts.Addr)
+	return http.ListenAndServe(serveOpts.Addr, server)
+}
+
+func doDiff(cmd *cobra.Command, cmdArgs []string) error {
+	initCommandLogging(cmd)
+
+	_, _, report, err := compareSources(cmdArgs)
+	if err != nil {
+		return err
+	}
+
+	return writeReport(cmd, report)
+}
+
+func doCheckCompat(cmd *cobra.Command, cmdArgs []string) error {
+	initCommandLogging(cmd)
+
+	conf, old, report, err := compareSources(cmdArgs)
+	if err != nil {
+		return err
+	}
+
+	breaking, err := report.BreakingChanges(apidiff.ServedGroupVersions(old), conf.Compat.AllowedBreakingChanges)
+	if err != nil {
+		return err
+	}
+
+	if len(breaking.Entries) == 0 && diffFormat == "markdown" {
+		fmt.Fprintln(cmd.OutOrStdout(), "No breaking changes to served versions.")
+		return nil
+	}
+	if err := writeReport(cmd, breaking); err != nil {
+		return err
+	}
+
+	if len(breaking.Entries) > 0 {
+		return fmt.Errorf("found %d breaking change(s)", len(breaking.Entries))
+	}
+	return nil
+}
+
+// compareSources processes the two source trees or git revisions given as arguments and returns
+// the configuration, the old version of the API and the changes made to it.
+func compareSources(cmdArgs []string) (*config.Config, []types.GroupVersionDetails, *apidiff.Report, error) {
+	if diffFormat != "markdown" && diffFormat != "json" {
+		return nil, nil, nil, fmt.Errorf("unknown format %q", diffFormat)
+	}
+	if !diffGit && len(cmdArgs) != 2 {
+		return nil, nil, nil, fmt.Errorf("two source paths are required")
+	}
+
+	conf, err := config.Load(args)
+	if err != nil {
+		zap.S().Errorw("Failed to read config", "error", err)
+		return nil, nil, nil, err
+	}
+
+	sourcePaths := [][]string{conf.SourcePaths, conf.SourcePaths}
+	if !diffGit {
+		sourcePaths = [][]string{{cmdArgs[0]}, {cmdArgs[1]}}
+	} else {
+		for i, revision := range cmdArgs {
+			paths, cleanup, err := apidiff.Worktree(conf.SourcePaths, revision)
+			if err != nil {
+				zap.S().Errorw("Failed to check out revision", "revision", revision, "error", err)
+				return nil, nil, nil, err
+			}
+			defer func() {
+				if err := cleanup(); err != nil {
+					zap.S().Warnw("Failed to remove worktree", "revision", revision, "error", err)
+				}
+			}()
+			sourcePaths[i] = paths
+		}
+	}
+
+	versions := make([][]types.GroupVersionDetails, len(sourcePaths))
+	for i, paths := range sourcePaths {
+		c := *conf
+		c.SourcePaths = paths
+		if diffGit && i < len(cmdArgs) {
+			// worktrees are temporary, and the overlay replaces files of the working tree
+			c.CacheDir = ""
+			c.Overlay = nil
+		}
+		if versions[i], err = process(&c); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return conf, versions[0], apidiff.Compare(versions[0], versions[1]), nil
+}
+
+func writeReport(cmd *cobra.Command, report *apidiff.Report) error {
+	if diffFormat == "json" {
+		return report.WriteJSON(cmd.OutOrStdout())
+	}
+	return report.WriteMarkdown(cmd.OutOrStdout())
+}
+
+func process(conf *config.Config) ([]types.GroupVersionDetails, error) {
+	zap.S().Infow("Processing source directories", "directories", conf.SourcePaths, "depth", conf.MaxDepth)
+	gvd, err := processor.Process(conf)
+	if err != nil {
+		zap.S().Errorw("Failed to process source directories", "error", err)
+		return nil, err
+	}
+	return gvd, nil
+}
+
+// initCommandLogging initializes logging for commands that print their results to the console.
+// Informational messages are omitted unless a log level is requested explicitly.
+func initCommandLogging(cmd *cobra.Command) {
+	level := args.LogLevel
+	if !cmd.Flags().Changed("log-level") {
+		level = "WARN"
+	}
+	initLogging(level)
+}
+
 func initLogging(level string) {
 	var logger *zap.Logger
 	var err error
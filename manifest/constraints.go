@@ -0,0 +1,172 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/goccy/go-yaml/ast"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// basicKinds maps the names of basic Go types to the kind of YAML value they are serialized as.
+var basicKinds = map[string]string{
+	"string":  "string",
+	"bool":    "boolean",
+	"int":     "integer",
+	"int8":    "integer",
+	"int16":   "integer",
+	"int32":   "integer",
+	"int64":   "integer",
+	"uint":    "integer",
+	"uint8":   "integer",
+	"uint16":  "integer",
+	"uint32":  "integer",
+	"uint64":  "integer",
+	"byte":    "integer",
+	"rune":    "integer",
+	"float32": "number",
+	"float64": "number",
+}
+
+func isByteType(t *types.Type) bool {
+	return t != nil && t.Kind == types.BasicKind && (t.Name == "byte" || t.Name == "uint8")
+}
+
+// nodeKind returns the kind of YAML value held by the node, named as in error messages of the API server.
+func nodeKind(node ast.Node) string {
+	switch node.(type) {
+	case *ast.StringNode, *ast.LiteralNode:
+		return "string"
+	case *ast.IntegerNode:
+		return "integer"
+	case *ast.FloatNode, *ast.InfinityNode, *ast.NanNode:
+		return "number"
+	case *ast.BoolNode:
+		return "boolean"
+	case *ast.NullNode:
+		return "null"
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return "object"
+	case *ast.SequenceNode:
+		return "array"
+	default:
+		return node.Type().String()
+	}
+}
+
+func (c *checker) checkScalar(node ast.Node, path, kind string, constraints []markers.MarkerValues) {
+	actual := nodeKind(node)
+	if actual != kind && (kind != "number" || actual != "integer") {
+		c.report(node, path, "expected %s, got %s", kind, actual)
+		return
+	}
+
+	value := scalarString(node)
+	for _, m := range constraints {
+		if enum, ok := m.Get(types.EnumMarker).(crdmarkers.Enum); ok && !inEnum(enum, value) {
+			c.report(node, path, "%q is not one of the allowed values: %s", value, joinValues(enum))
+		}
+
+		switch kind {
+		case "string":
+			length := utf8.RuneCountInString(value)
+			if v, ok := m.Get(types.MaxLengthMarker).(crdmarkers.MaxLength); ok && length > int(v) {
+				c.report(node, path, "must be at most %d characters long", v)
+			}
+			if v, ok := m.Get(types.MinLengthMarker).(crdmarkers.MinLength); ok && length < int(v) {
+				c.report(node, path, "must be at least %d characters long", v)
+			}
+			if v, ok := m.Get(types.PatternMarker).(crdmarkers.Pattern); ok {
+				// patterns the RE2 engine cannot compile are not checked
+				if re, err := regexp.Compile(string(v)); err == nil && !re.MatchString(value) {
+					c.report(node, path, "%q does not match the pattern %s", value, v)
+				}
+			}
+
+		case "integer", "number":
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			if v, ok := m.Get(types.MaximumMarker).(crdmarkers.Maximum); ok {
+				exclusive, _ := m.Get(types.ExclusiveMaximumMarker).(crdmarkers.ExclusiveMaximum)
+				if number > float64(v) || (bool(exclusive) && number == float64(v)) {
+					c.report(node, path, "must be less than %s%v", orEqual(!bool(exclusive)), v)
+				}
+			}
+			if v, ok := m.Get(types.MinimumMarker).(crdmarkers.Minimum); ok {
+				exclusive, _ := m.Get(types.ExclusiveMinimumMarker).(crdmarkers.ExclusiveMinimum)
+				if number < float64(v) || (bool(exclusive) && number == float64(v)) {
+					c.report(node, path, "must be greater than %s%v", orEqual(!bool(exclusive)), v)
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) checkItems(node ast.Node, path string, count int, constraints []markers.MarkerValues) {
+	for _, m := range constraints {
+		if v, ok := m.Get(types.MaxItemsMarker).(crdmarkers.MaxItems); ok && count > int(v) {
+			c.report(node, path, "must have at most %d items", v)
+		}
+		if v, ok := m.Get(types.MinItemsMarker).(crdmarkers.MinItems); ok && count < int(v) {
+			c.report(node, path, "must have at least %d items", v)
+		}
+	}
+}
+
+func (c *checker) checkProperties(node ast.Node, path string, count int, constraints []markers.MarkerValues) {
+	for _, m := range constraints {
+		if v, ok := m.Get(types.MaxPropertiesMarker).(crdmarkers.MaxProperties); ok && count > int(v) {
+			c.report(node, path, "must have at most %d properties", v)
+		}
+		if v, ok := m.Get(types.MinPropertiesMarker).(crdmarkers.MinProperties); ok && count < int(v) {
+			c.report(node, path, "must have at least %d properties", v)
+		}
+	}
+}
+
+func inEnum(enum crdmarkers.Enum, value string) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValues(enum crdmarkers.Enum) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
+
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
@@ -0,0 +1,415 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package manifest loads YAML manifests and checks them against the processed types.
+package manifest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+var documentSeparatorRegex = regexp.MustCompile(`^---(\s|$)`)
+
+// opaqueTypes holds the keys of types whose serialized form is not described by their Go fields.
+var opaqueTypes = map[string]bool{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                          true,
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                     true,
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                      true,
+	"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                      true,
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                      true,
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                    true,
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                       true,
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON":      true,
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1.JSON": true,
+}
+
+// Document is a single YAML document read from a manifest file.
+type Document struct {
+	// Path of the file the document was read from.
+	Path string
+	// Line of the file on which the document starts.
+	Line       int
+	Content    string
+	APIVersion string
+	Kind       string
+	body       ast.Node
+}
+
+// Problem describes a part of a document that does not match the type of its Kind.
+type Problem struct {
+	Path    string
+	Line    int
+	Column  int
+	Field   string
+	Message string
+	// Warning is true when the document could not be checked, rather than found invalid.
+	Warning bool
+}
+
+func (p Problem) String() string {
+	message := p.Message
+	if p.Warning {
+		message = "warning: " + message
+	}
+	if p.Field == "" {
+		return fmt.Sprintf("%s:%d:%d: %s", p.Path, p.Line, p.Column, message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", p.Path, p.Line, p.Column, p.Field, message)
+}
+
+// Load reads the YAML documents from the given file, or from all YAML files found in the given directory.
+func Load(path string) ([]*Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return loadFile(path)
+	}
+
+	var docs []*Document
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !isYAMLFile(p) {
+			return nil
+		}
+
+		fileDocs, err := loadFile(p)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, fileDocs...)
+		return nil
+	})
+
+	return docs, err
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func loadFile(path string) ([]*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// documents are parsed separately to keep hold of their content
+	var docs []*Document
+	lines := strings.SplitAfter(string(b), "\n")
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !documentSeparatorRegex.MatchString(lines[i]) {
+			continue
+		}
+
+		doc, err := parseDocument(path, start, strings.Join(lines[start:i], ""))
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+		start = i + 1
+	}
+
+	return docs, nil
+}
+
+// parseDocument parses a document whose content starts after the given number of lines of its file.
+func parseDocument(path string, offset int, content string) (*Document, error) {
+	f, err := parser.ParseBytes([]byte(content), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return nil, nil
+	}
+
+	doc := &Document{
+		Path:    path,
+		Line:    offset + 1,
+		Content: content,
+		body:    f.Docs[0].Body,
+	}
+	for _, mv := range mappingValues(doc.body) {
+		switch keyName(mv.Key) {
+		case "apiVersion":
+			doc.APIVersion = scalarString(mv.Value)
+		case "kind":
+			doc.Kind = scalarString(mv.Value)
+		}
+	}
+
+	return doc, nil
+}
+
+// FindKind returns the root type of the Kind with the given API version and name.
+func FindKind(gvd []types.GroupVersionDetails, apiVersion, kind string) *types.Type {
+	for _, gv := range gvd {
+		if gv.GroupVersionString() != apiVersion {
+			continue
+		}
+
+		for _, k := range gv.Kinds {
+			if t := gv.TypeForKind(k); k == kind && t != nil && t.GVK != nil {
+				return t
+			}
+		}
+	}
+
+	return nil
+}
+
+// IgnoredField returns true if the field with the given name was excluded from the given type
+// when processing it, such as the fields matched by the ignoreFields option. Documents may set
+// such fields, which are not checked.
+type IgnoredField func(t *types.Type, name string) bool
+
+// Validate returns the problems found by comparing the document with the Kind it declares.
+// Documents of groups that are not documented, such as built-in Kinds or Kinds of other
+// operators, cannot be checked and are reported as warnings.
+func Validate(gvd []types.GroupVersionDetails, doc *Document, ignored IgnoredField) []Problem {
+	if doc.APIVersion == "" || doc.Kind == "" {
+		return []Problem{{Path: doc.Path, Line: doc.Line, Column: 1, Message: "apiVersion and kind must be set"}}
+	}
+
+	t := FindKind(gvd, doc.APIVersion, doc.Kind)
+	if t == nil {
+		if !hasGroup(gvd, doc.APIVersion) {
+			return []Problem{{Path: doc.Path, Line: doc.Line, Column: 1, Warning: true, Message: fmt.Sprintf("skipped %s %s: group not documented", doc.APIVersion, doc.Kind)}}
+		}
+		return []Problem{{Path: doc.Path, Line: doc.Line, Column: 1, Message: fmt.Sprintf("no Kind %s found in %s", doc.Kind, doc.APIVersion)}}
+	}
+
+	return Check(doc, t, ignored)
+}
+
+// hasGroup returns true if the group of the given API version is one of the processed groups.
+func hasGroup(gvd []types.GroupVersionDetails, apiVersion string) bool {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return false
+	}
+	for _, d := range gvd {
+		if d.Group == gv.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the problems found by comparing the document with the given root type: unknown
+// fields, values of the wrong type and values violating the constraints declared by validation markers.
+// Fields for which ignored, if not nil, returns true are skipped.
+func Check(doc *Document, t *types.Type, ignored IgnoredField) []Problem {
+	c := &checker{doc: doc, ignored: ignored}
+	c.check(doc.body, t, "", nil)
+	return c.problems
+}
+
+type checker struct {
+	doc      *Document
+	ignored  IgnoredField
+	problems []Problem
+}
+
+func (c *checker) report(node ast.Node, field, format string, args ...any) {
+	p := Problem{Path: c.doc.Path, Field: field, Message: fmt.Sprintf(format, args...)}
+	if tk := node.GetToken(); tk != nil && tk.Position != nil {
+		p.Line = c.doc.Line - 1 + tk.Position.Line
+		p.Column = tk.Position.Column
+	}
+	c.problems = append(c.problems, p)
+}
+
+// check compares a node with the given type. The markers of the field holding the node, if any,
+// are checked along with those of the type and of the types it is an alias of.
+func (c *checker) check(node ast.Node, t *types.Type, path string, fieldMarkers markers.MarkerValues) {
+	node = unwrap(node)
+	if node == nil || node.Type() == ast.NullType {
+		return
+	}
+
+	constraints := []markers.MarkerValues{fieldMarkers}
+	for t != nil && (t.Kind == types.PointerKind || t.Kind == types.AliasKind) {
+		constraints = append(constraints, t.Markers)
+		t = t.UnderlyingType
+	}
+	if t == nil || opaqueTypes[types.Key(t)] {
+		return
+	}
+	constraints = append(constraints, t.Markers)
+
+	switch t.Kind {
+	case types.StructKind:
+		values, ok := c.mapping(node, path)
+		if !ok {
+			return
+		}
+		c.checkProperties(node, path, len(values), constraints)
+
+		members := t.Members()
+		if len(members) == 0 {
+			// the fields of the type are not known
+			return
+		}
+
+		for _, mv := range values {
+			name := keyName(mv.Key)
+			if path == "" && (name == "apiVersion" || name == "kind") {
+				continue
+			}
+
+			fieldPath := joinPath(path, name)
+			f := findField(members, name)
+			if f == nil && c.ignored != nil && c.ignored(t, name) {
+				continue
+			}
+			if f == nil {
+				c.report(mv.Key, fieldPath, "unknown field %q", name)
+				continue
+			}
+			c.check(mv.Value, f.Type, fieldPath, f.Markers)
+		}
+
+	case types.MapKind:
+		values, ok := c.mapping(node, path)
+		if !ok {
+			return
+		}
+		c.checkProperties(node, path, len(values), constraints)
+
+		for _, mv := range values {
+			c.check(mv.Value, t.ValueType, fmt.Sprintf("%s[%s]", path, keyName(mv.Key)), nil)
+		}
+
+	case types.SliceKind, types.ArrayKind:
+		if isByteType(t.UnderlyingType) {
+			// byte slices are serialized as base64 strings
+			c.checkScalar(node, path, "string", constraints)
+			return
+		}
+
+		seq, ok := node.(*ast.SequenceNode)
+		if !ok {
+			c.report(node, path, "expected array, got %s", nodeKind(node))
+			return
+		}
+		c.checkItems(node, path, len(seq.Values), constraints)
+
+		for i, v := range seq.Values {
+			c.check(v, t.UnderlyingType, fmt.Sprintf("%s[%d]", path, i), nil)
+		}
+
+	case types.BasicKind:
+		if kind := basicKinds[t.Name]; kind != "" {
+			c.checkScalar(node, path, kind, constraints)
+		}
+	}
+}
+
+func (c *checker) mapping(node ast.Node, path string) ([]*ast.MappingValueNode, bool) {
+	switch node.(type) {
+	case *ast.MappingNode, *ast.MappingValueNode:
+		return mappingValues(node), true
+	default:
+		c.report(node, path, "expected object, got %s", nodeKind(node))
+		return nil, false
+	}
+}
+
+func findField(fields types.Fields, name string) *types.Field {
+	for _, f := range fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// unwrap returns the node holding the value of tagged and anchored nodes.
+func unwrap(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.TagNode:
+			node = n.Value
+		case *ast.AnchorNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+// mappingValues returns the key-value pairs of a mapping. A mapping with a single pair may be
+// represented by the pair itself.
+func mappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch n := unwrap(node).(type) {
+	case *ast.MappingNode:
+		return n.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}
+	default:
+		return nil
+	}
+}
+
+func keyName(key ast.MapKeyNode) string {
+	if s, ok := key.(*ast.StringNode); ok {
+		return s.Value
+	}
+	return key.String()
+}
+
+func scalarString(node ast.Node) string {
+	switch n := unwrap(node).(type) {
+	case *ast.StringNode:
+		return n.Value
+	case *ast.LiteralNode:
+		return n.Value.Value
+	case nil:
+		return ""
+	default:
+		return n.String()
+	}
+}
@@ -0,0 +1,193 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestLoadAndCheck(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	item := &types.Type{Name: "Item", Package: "example.com/api/v1", Kind: types.StructKind}
+	item.Fields = types.Fields{{Name: "name", Type: str}}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "items", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: item}},
+		{Name: "labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}},
+	}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec, "Item": item},
+	}}
+
+	dir := t.TempDir()
+	content := `# a valid sample
+apiVersion: example.com/v1
+kind: Thing
+spec:
+  labels:
+    app: demo
+---
+apiVersion: example.com/v1
+kind: Thing
+spec:
+  items:
+    - name: a
+    - nmae: b
+  replicas: 1
+---
+apiVersion: example.com/v2
+kind: Thing
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "things.yaml"), []byte(content), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o644))
+
+	docs, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	require.Equal(t, 1, docs[0].Line)
+	require.Equal(t, 8, docs[1].Line)
+	require.Equal(t, "example.com/v1", docs[1].APIVersion)
+	require.Equal(t, "Thing", docs[1].Kind)
+
+	require.Same(t, thing, FindKind(gvd, docs[0].APIVersion, docs[0].Kind))
+	require.Nil(t, FindKind(gvd, docs[2].APIVersion, docs[2].Kind))
+
+	require.Empty(t, Check(docs[0], thing, nil))
+
+	problems := Check(docs[1], thing, nil)
+	require.Len(t, problems, 2)
+	require.Equal(t, "spec.items[1].nmae", problems[0].Field)
+	require.Equal(t, 13, problems[0].Line)
+	require.Equal(t, "spec.replicas", problems[1].Field)
+	require.Equal(t, 14, problems[1].Line)
+}
+
+func TestValidate(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	integer := &types.Type{Name: "int32", Kind: types.BasicKind}
+	level := &types.Type{
+		Name:           "Level",
+		Package:        "example.com/api/v1",
+		Kind:           types.AliasKind,
+		UnderlyingType: str,
+		Markers:        markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum{"low", "high"}}},
+	}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "replicas", Type: &types.Type{Kind: types.PointerKind, UnderlyingType: integer}, Markers: markers.MarkerValues{
+			types.MinimumMarker: {crdmarkers.Minimum(1)},
+			types.MaximumMarker: {crdmarkers.Maximum(5)},
+		}},
+		{Name: "name", Type: str, Markers: markers.MarkerValues{
+			types.MaxLengthMarker: {crdmarkers.MaxLength(4)},
+			types.PatternMarker:   {crdmarkers.Pattern("^[a-z]+$")},
+		}},
+		{Name: "level", Type: level},
+		{Name: "tags", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: str}, Markers: markers.MarkerValues{
+			types.MaxItemsMarker: {crdmarkers.MaxItems(1)},
+		}},
+		{Name: "data", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Name: "byte", Kind: types.BasicKind}}},
+	}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec, "Level": level},
+	}}
+
+	path := filepath.Join(t.TempDir(), "thing.yaml")
+	content := `apiVersion: example.com/v1
+kind: Thing
+spec:
+  replicas: 6
+  name: Thing1
+  level: medium
+  tags: [a, b]
+  data: aGVsbG8=
+status:
+  ready: true
+---
+apiVersion: example.com/v1
+kind: Thing
+spec: []
+---
+apiVersion: example.com/v1
+kind: Other
+---
+kind: Thing
+---
+apiVersion: example.com/v2
+kind: Thing
+---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  unknown: true
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	docs, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, docs, 6)
+
+	ignored := func(t *types.Type, name string) bool {
+		return t == thing && name == "status"
+	}
+
+	var got []string
+	for _, doc := range docs {
+		for _, p := range Validate(gvd, doc, ignored) {
+			got = append(got, p.String())
+		}
+	}
+
+	require.Equal(t, []string{
+		path + ":4:13: spec.replicas: must be less than or equal to 5",
+		path + ":5:9: spec.name: must be at most 4 characters long",
+		path + `:5:9: spec.name: "Thing1" does not match the pattern ^[a-z]+$`,
+		path + `:6:10: spec.level: "medium" is not one of the allowed values: low, high`,
+		path + ":7:9: spec.tags: must have at most 1 items",
+		path + ":14:7: spec: expected object, got array",
+		path + ":16:1: no Kind Other found in example.com/v1",
+		path + ":19:1: apiVersion and kind must be set",
+		path + ":21:1: no Kind Thing found in example.com/v2",
+		path + ":24:1: warning: skipped apps/v1 Deployment: group not documented",
+	}, got)
+}
@@ -0,0 +1,625 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"go.uber.org/zap"
+	"golang.org/x/tools/go/packages"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// cacheFormat is the version of the format of cache files. It must be increased whenever the
+// format or the output of the processor changes.
+const cacheFormat = 4
+
+// cachedModel holds the types processed from the packages of the source paths, before they are
+// completed by inlining embedded types and collecting references, along with the keys of the
+// inputs they were processed from. The types of the packages whose key did not change are reused,
+// and only the other packages are loaded again.
+type cachedModel struct {
+	Key      string
+	Packages map[string]string // keys of the packages of the main modules, by import path
+	Roots    []string          // import paths of the packages matched by the patterns
+	// APIPackages are the root packages holding a group version.
+	APIPackages []cachedPackage
+	Types       []cachedType
+	Fields      []cachedField
+	// ProcessedTypes maps the keys of the processed types to their index.
+	ProcessedTypes map[string]int
+}
+
+// cachedType is a type of the model. Types and fields refer to each other by their index plus
+// one in the model, zero standing for nil, as the model is a graph with cycles.
+type cachedType struct {
+	Name            string
+	Package         string
+	Doc             string
+	GVK             *schema.GroupVersionKind
+	Kind            types.Kind
+	Imported        bool
+	UnderlyingType  int
+	KeyType         int
+	ValueType       int
+	Fields          []int
+	References      []int
+	Markers         []cachedMarker
+	Validation      []string
+	ListKind        string
+	FieldReferences []cachedFieldReference
+}
+
+type cachedFieldReference struct {
+	Type  int
+	Field string
+	Paths []string
+}
+
+type cachedField struct {
+	Name       string
+	GoName     string
+	Embedded   bool
+	Inlined    bool
+	Doc        string
+	Type       int
+	Markers    []cachedMarker
+	Validation []string
+	Default    any
+	Optional   bool
+}
+
+// cachedMarker holds the values of a marker. Markers without arguments are struct values
+// without fields, which gob cannot encode: only their count is kept.
+type cachedMarker struct {
+	Name   string
+	Values []any
+	Empty  int
+}
+
+// cachedPackage is the part of a group version declared in a package.
+type cachedPackage struct {
+	Path    string
+	Group   string
+	Version string
+	Doc     string
+	Kinds   []string
+	Types   map[string]int
+}
+
+var (
+	registerMarkersOnce sync.Once
+	// emptyMarkers maps the names of the markers without arguments to their type.
+	emptyMarkers map[string]reflect.Type
+)
+
+// registerMarkers registers the types of the marker values with gob, so that they can be encoded
+// as interface values.
+func registerMarkers() {
+	registerMarkersOnce.Do(func() {
+		gob.Register([]any{})
+		gob.Register(map[string]any{})
+
+		emptyMarkers = make(map[string]reflect.Type)
+		for _, def := range mkRegistry().AllDefinitions() {
+			if def.Output.Kind() == reflect.Struct && def.Output.NumField() == 0 {
+				emptyMarkers[def.Name] = def.Output
+				continue
+			}
+			gob.Register(reflect.Zero(def.Output).Interface())
+		}
+	})
+}
+
+// cacheInputs identifies the inputs of the processing of the source paths.
+type cacheInputs struct {
+	// key identifies the inputs shared by all the packages: the go.mod, go.sum and go.work files
+	// of the main modules and the processor configuration.
+	key string
+	// packages maps the import paths of the packages of the main modules to the keys of their Go
+	// files and of the packages of the main modules they import.
+	packages map[string]string
+	// roots lists the import paths of the packages matched by the patterns, by source path.
+	roots map[string][]string
+}
+
+// readCacheInputs lists the packages of the source paths and computes the keys of their inputs,
+// without type-checking them.
+func readCacheInputs(conf *config.Config, cc *compiledConfig) (*cacheInputs, error) {
+	inputs := &cacheInputs{packages: make(map[string]string), roots: make(map[string][]string)}
+	fileHashes := make(map[string]string)
+	imports := make(map[string][]string)
+	sharedFiles := make(map[string]struct{})
+	for _, directory := range conf.SourcePaths {
+		patterns, err := workspacePatterns(directory, cc.patterns())
+		if err != nil {
+			return nil, err
+		}
+
+		mode := packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule
+		pkgs, err := packages.Load(cc.packagesConfig(directory, mode), patterns...)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range pkgs {
+			inputs.roots[directory] = append(inputs.roots[directory], pkg.PkgPath)
+		}
+
+		var visitErr error
+		packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+			if visitErr != nil || pkg.Module == nil || !pkg.Module.Main {
+				return
+			}
+			if _, ok := fileHashes[pkg.PkgPath]; ok {
+				return
+			}
+
+			fileHashes[pkg.PkgPath], visitErr = hashFiles(pkg.GoFiles, conf.Overlay)
+			for _, imp := range pkg.Imports {
+				imports[pkg.PkgPath] = append(imports[pkg.PkgPath], imp.PkgPath)
+			}
+			if pkg.Module.GoMod != "" {
+				sharedFiles[pkg.Module.GoMod] = struct{}{}
+				sharedFiles[filepath.Join(filepath.Dir(pkg.Module.GoMod), "go.sum")] = struct{}{}
+			}
+		})
+		if visitErr != nil {
+			return nil, visitErr
+		}
+
+		workFile, err := findWorkFile(directory)
+		if err != nil {
+			return nil, err
+		}
+		if workFile != "" {
+			sharedFiles[workFile] = struct{}{}
+			sharedFiles[workFile+".sum"] = struct{}{}
+		}
+	}
+
+	// the types of a package depend on the packages it imports: the types of packages of other
+	// modules are covered by the go.mod and go.sum files
+	var packageKey func(pkg string) string
+	packageKey = func(pkg string) string {
+		if key, ok := inputs.packages[pkg]; ok {
+			return key
+		}
+
+		h := sha256.New()
+		fmt.Fprintf(h, "%s\n", fileHashes[pkg])
+		deps := imports[pkg]
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := fileHashes[dep]; ok {
+				fmt.Fprintf(h, "%s %s\n", dep, packageKey(dep))
+			}
+		}
+		key := hex.EncodeToString(h.Sum(nil))
+		inputs.packages[pkg] = key
+		return key
+	}
+	for pkg := range fileHashes {
+		packageKey(pkg)
+	}
+
+	// samples and releases are not part of the cached model
+	processorConf := conf.Processor
+	processorConf.SamplesPath = ""
+	processorConf.Releases = nil
+	encodedConf, err := json.Marshal(processorConf)
+	if err != nil {
+		return nil, err
+	}
+	sharedHash, err := hashFiles(sortedKeys(sharedFiles), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%d\n%s\n%s\n", cacheFormat, conf.MaxDepth, encodedConf, sharedHash)
+	inputs.key = hex.EncodeToString(h.Sum(nil))
+	return inputs, nil
+}
+
+// hashFiles returns the hash of the names and contents of the files, taking their contents from
+// the overlay when it replaces them and ignoring missing files.
+func hashFiles(files []string, overlay map[string][]byte) (string, error) {
+	h := sha256.New()
+	for _, name := range files {
+		src, err := readFile(name, overlay)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "%s\n%d\n", filepath.Base(name), len(src))
+		h.Write(src)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cachePath returns the path of the cache file of the source paths in the cache directory.
+func cachePath(conf *config.Config) (string, error) {
+	h := sha256.New()
+	for _, path := range conf.SourcePaths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\n", abs)
+	}
+	sum := h.Sum(nil)
+	return filepath.Join(conf.CacheDir, hex.EncodeToString(sum[:8])+".gob"), nil
+}
+
+// loadCache returns the cached model of the source paths if it was processed from the shared
+// inputs identified by key, or nil.
+func loadCache(path, key string) *cachedModel {
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			zap.S().Warnw("Failed to read cache", "path", path, "error", err)
+		}
+		return nil
+	}
+	defer f.Close()
+
+	registerMarkers()
+	var model cachedModel
+	if err := gob.NewDecoder(f).Decode(&model); err != nil {
+		zap.S().Warnw("Failed to read cache", "path", path, "error", err)
+		return nil
+	}
+
+	if model.Key != key {
+		zap.S().Debugw("Cache is out of date", "path", path)
+		return nil
+	}
+	return &model
+}
+
+// storeCache writes the cached model of the source paths.
+func storeCache(path string, model *cachedModel) error {
+	registerMarkers()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that concurrent runs never read a partial cache
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(model); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// restore adds the types and group versions processed from the packages that did not change
+// since the model was cached, and returns the import paths of the root packages that do not need
+// to be loaded again. The types of a package are reused when neither its Go files nor the
+// packages of the main modules it imports changed.
+func (p *processor) restore(m *cachedModel, inputs *cacheInputs) map[string]bool {
+	reused := make(map[string]bool)
+	if m == nil {
+		return reused
+	}
+
+	unchanged := func(pkg string) bool {
+		key, current := inputs.packages[pkg]
+		cachedKey, cached := m.Packages[pkg]
+		if !current && !cached {
+			// the packages of other modules are covered by the shared key
+			return true
+		}
+		return current && cached && key == cachedKey
+	}
+
+	typ := m.decodeTypes()
+	for key, id := range m.ProcessedTypes {
+		t := typ(id)
+		if t == nil || !unchanged(t.Package) {
+			continue
+		}
+
+		p.types[key] = t
+		for _, f := range t.Fields {
+			p.addReference(t, f.Type)
+		}
+	}
+
+	wasRoot := make(map[string]bool, len(m.Roots))
+	for _, pkg := range m.Roots {
+		wasRoot[pkg] = true
+	}
+	for _, roots := range inputs.roots {
+		for _, pkg := range roots {
+			if wasRoot[pkg] && unchanged(pkg) {
+				reused[pkg] = true
+				p.loadedPackages[pkg] = struct{}{}
+			}
+		}
+	}
+
+	for _, cp := range m.APIPackages {
+		if !reused[cp.Path] {
+			continue
+		}
+
+		pkgInfo := apiPackage{GroupVersion: schema.GroupVersion{Group: cp.Group, Version: cp.Version}, doc: cp.Doc}
+		p.apiPackages[cp.Path] = pkgInfo
+		gvInfo := p.addGroupVersion(&groupVersionInfo{GroupVersion: pkgInfo.GroupVersion, doc: pkgInfo.doc})
+		for name, id := range cp.Types {
+			if t := typ(id); t != nil {
+				gvInfo.types[name] = t
+			}
+		}
+		for _, kind := range cp.Kinds {
+			gvInfo.kinds[kind] = struct{}{}
+		}
+	}
+
+	return reused
+}
+
+// snapshot returns the model to cache: the types and group versions processed so far, which
+// must not be completed yet, along with the inputs they were processed from.
+func (p *processor) snapshot(inputs *cacheInputs) *cachedModel {
+	m := &cachedModel{Key: inputs.key, Packages: inputs.packages, ProcessedTypes: make(map[string]int, len(p.types))}
+	enc := newModelEncoder(m)
+	for _, key := range sortedKeys(p.types) {
+		m.ProcessedTypes[key] = enc.typeID(p.types[key])
+	}
+
+	roots := make(map[string]struct{})
+	for _, pkgs := range inputs.roots {
+		for _, pkg := range pkgs {
+			roots[pkg] = struct{}{}
+		}
+	}
+	m.Roots = sortedKeys(roots)
+
+	for _, path := range sortedKeys(p.apiPackages) {
+		pkgInfo := p.apiPackages[path]
+		gvInfo := p.groupVersions[pkgInfo.GroupVersion]
+		cp := cachedPackage{Path: path, Group: pkgInfo.Group, Version: pkgInfo.Version, Doc: pkgInfo.doc, Types: make(map[string]int)}
+		for name, t := range gvInfo.types {
+			if t.Package == path {
+				cp.Types[name] = enc.typeID(t)
+			}
+		}
+		for kind := range gvInfo.kinds {
+			if t := gvInfo.types[kind]; t != nil && t.Package == path {
+				cp.Kinds = append(cp.Kinds, kind)
+			}
+		}
+		sort.Strings(cp.Kinds)
+		m.APIPackages = append(m.APIPackages, cp)
+	}
+
+	return m
+}
+
+// modelEncoder adds types and fields to a cached model, each of them once.
+type modelEncoder struct {
+	m        *cachedModel
+	typeIDs  map[*types.Type]int
+	fieldIDs map[*types.Field]int
+}
+
+func newModelEncoder(m *cachedModel) *modelEncoder {
+	registerMarkers()
+	return &modelEncoder{m: m, typeIDs: make(map[*types.Type]int), fieldIDs: make(map[*types.Field]int)}
+}
+
+func (e *modelEncoder) typeID(t *types.Type) int {
+	if t == nil {
+		return 0
+	}
+	if id, ok := e.typeIDs[t]; ok {
+		return id
+	}
+
+	e.m.Types = append(e.m.Types, cachedType{})
+	id := len(e.m.Types)
+	e.typeIDs[t] = id
+
+	ct := cachedType{
+		Name:       t.Name,
+		Package:    t.Package,
+		Doc:        t.Doc,
+		GVK:        t.GVK,
+		Kind:       t.Kind,
+		Imported:   t.Imported,
+		Markers:    encodeMarkers(t.Markers),
+		Validation: t.Validation,
+		ListKind:   t.ListKind,
+	}
+	ct.UnderlyingType = e.typeID(t.UnderlyingType)
+	ct.KeyType = e.typeID(t.KeyType)
+	ct.ValueType = e.typeID(t.ValueType)
+	for _, f := range t.Fields {
+		ct.Fields = append(ct.Fields, e.fieldID(f))
+	}
+	for _, r := range t.References {
+		ct.References = append(ct.References, e.typeID(r))
+	}
+	for _, r := range t.FieldReferences {
+		ct.FieldReferences = append(ct.FieldReferences, cachedFieldReference{Type: e.typeID(r.Type), Field: r.Field, Paths: r.Paths})
+	}
+	e.m.Types[id-1] = ct
+	return id
+}
+
+func (e *modelEncoder) fieldID(f *types.Field) int {
+	if id, ok := e.fieldIDs[f]; ok {
+		return id
+	}
+
+	e.m.Fields = append(e.m.Fields, cachedField{})
+	id := len(e.m.Fields)
+	e.fieldIDs[f] = id
+
+	e.m.Fields[id-1] = cachedField{
+		Name:       f.Name,
+		GoName:     f.GoName,
+		Embedded:   f.Embedded,
+		Inlined:    f.Inlined,
+		Doc:        f.Doc,
+		Type:       e.typeID(f.Type),
+		Markers:    encodeMarkers(f.Markers),
+		Validation: f.Validation,
+		Default:    f.Default,
+		Optional:   f.Optional,
+	}
+	return id
+}
+
+// decodeTypes decodes the types and fields of the model, and returns the function looking up
+// the types by their index.
+func (m *cachedModel) decodeTypes() func(id int) *types.Type {
+	typeList := make([]*types.Type, len(m.Types))
+	for i := range typeList {
+		typeList[i] = &types.Type{}
+	}
+	fieldList := make([]*types.Field, len(m.Fields))
+	for i := range fieldList {
+		fieldList[i] = &types.Field{}
+	}
+
+	typ := func(id int) *types.Type {
+		if id == 0 {
+			return nil
+		}
+		return typeList[id-1]
+	}
+
+	for i, cf := range m.Fields {
+		*fieldList[i] = types.Field{
+			Name:       cf.Name,
+			GoName:     cf.GoName,
+			Embedded:   cf.Embedded,
+			Inlined:    cf.Inlined,
+			Doc:        cf.Doc,
+			Type:       typ(cf.Type),
+			Markers:    decodeMarkers(cf.Markers),
+			Validation: cf.Validation,
+			Default:    cf.Default,
+			Optional:   cf.Optional,
+		}
+	}
+
+	for i, ct := range m.Types {
+		t := typeList[i]
+		*t = types.Type{
+			Name:           ct.Name,
+			Package:        ct.Package,
+			Doc:            ct.Doc,
+			GVK:            ct.GVK,
+			Kind:           ct.Kind,
+			Imported:       ct.Imported,
+			UnderlyingType: typ(ct.UnderlyingType),
+			KeyType:        typ(ct.KeyType),
+			ValueType:      typ(ct.ValueType),
+			Markers:        decodeMarkers(ct.Markers),
+			Validation:     ct.Validation,
+			ListKind:       ct.ListKind,
+		}
+		for _, id := range ct.Fields {
+			t.Fields = append(t.Fields, fieldList[id-1])
+		}
+		for _, id := range ct.References {
+			t.References = append(t.References, typ(id))
+		}
+		for _, r := range ct.FieldReferences {
+			t.FieldReferences = append(t.FieldReferences, types.FieldReference{Type: typ(r.Type), Field: r.Field, Paths: r.Paths})
+		}
+	}
+
+	return typ
+}
+
+func encodeMarkers(values markers.MarkerValues) []cachedMarker {
+	if values == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	encoded := make([]cachedMarker, 0, len(names))
+	for _, name := range names {
+		m := cachedMarker{Name: name}
+		if _, ok := emptyMarkers[name]; ok {
+			m.Empty = len(values[name])
+		} else {
+			m.Values = values[name]
+		}
+		encoded = append(encoded, m)
+	}
+	return encoded
+}
+
+func decodeMarkers(encoded []cachedMarker) markers.MarkerValues {
+	if encoded == nil {
+		return nil
+	}
+
+	values := make(markers.MarkerValues, len(encoded))
+	for _, m := range encoded {
+		vals := m.Values
+		for i := 0; i < m.Empty; i++ {
+			vals = append(vals, reflect.Zero(emptyMarkers[m.Name]).Interface())
+		}
+		values[m.Name] = vals
+	}
+	return values
+}
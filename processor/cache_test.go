@@ -0,0 +1,180 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-tools/pkg/crd/markers"
+	ctrlmarkers "sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	shared := &types.Field{Name: "name", GoName: "Name", Doc: "Name of the thing.", Type: str}
+	part := &types.Type{
+		Name:    "Part",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Fields:  types.Fields{shared},
+	}
+	thing := &types.Type{
+		Name:     "Thing",
+		Package:  "example.com/api/v1",
+		Doc:      "Thing is a thing.",
+		ListKind: "ThingList",
+		Kind:     types.StructKind,
+		GVK:      &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Markers:  ctrlmarkers.MarkerValues{objectRootMarker: {true}},
+		Fields: types.Fields{
+			shared,
+			{
+				Name:       "size",
+				GoName:     "Size",
+				Type:       &types.Type{Name: "int32", Kind: types.BasicKind},
+				Default:    float64(1),
+				Validation: []string{"Minimum: 1"},
+				Markers: ctrlmarkers.MarkerValues{
+					"kubebuilder:validation:Minimum":  {markers.Minimum(1)},
+					"kubebuilder:validation:Optional": {struct{}{}},
+				},
+				Optional: true,
+			},
+			{Name: "parts", GoName: "Parts", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: part}},
+			{Name: "labels", GoName: "Labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}},
+		},
+	}
+	part.References = []*types.Type{thing}
+	part.FieldReferences = []types.FieldReference{{Type: thing, Field: "parts", Paths: []string{"Thing.parts[]"}}}
+
+	p := newProcessor(nil, 6)
+	p.types = types.TypeMap{types.Key(thing): thing, types.Key(part): part, types.Key(str): str}
+	p.apiPackages["example.com/api/v1"] = apiPackage{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		doc:          "Package v1 contains the example API.",
+	}
+	gvInfo := p.addGroupVersion(&groupVersionInfo{GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"}})
+	gvInfo.types["Thing"] = thing
+	gvInfo.types["Part"] = part
+	gvInfo.kinds["Thing"] = struct{}{}
+
+	inputs := &cacheInputs{
+		key:      "key",
+		packages: map[string]string{"example.com/api/v1": "v1"},
+		roots:    map[string][]string{"api": {"example.com/api/v1"}},
+	}
+	path := filepath.Join(t.TempDir(), "cache", "api.gob")
+	require.NoError(t, storeCache(path, p.snapshot(inputs)))
+
+	require.Nil(t, loadCache(path, "other"))
+
+	loaded := newProcessor(nil, 6)
+	require.Equal(t, map[string]bool{"example.com/api/v1": true}, loaded.restore(loadCache(path, "key"), inputs))
+	require.Equal(t, p.apiPackages, loaded.apiPackages)
+	require.Contains(t, loaded.loadedPackages, "example.com/api/v1")
+
+	loadedInfo := loaded.groupVersions[schema.GroupVersion{Group: "example.com", Version: "v1"}]
+	require.Equal(t, gvInfo.kinds, loadedInfo.kinds)
+	require.Equal(t, thing, loadedInfo.types["Thing"])
+	require.Equal(t, part, loadedInfo.types["Part"])
+
+	loadedThing := loadedInfo.types["Thing"]
+	loadedPart := loadedInfo.types["Part"]
+	require.Same(t, loadedThing, loaded.types[types.Key(thing)])
+	require.Same(t, loadedThing.Fields[0], loadedPart.Fields[0])
+	require.Same(t, loadedThing, loadedPart.References[0])
+	require.Same(t, loadedPart, loadedThing.Fields[2].Type.UnderlyingType)
+	require.Same(t, loadedThing, loadedPart.FieldReferences[0].Type)
+	require.Contains(t, loaded.references[types.Key(part)], types.Key(thing))
+
+	// the types of a package whose key changed are not reused
+	changed := newProcessor(nil, 6)
+	inputs.packages["example.com/api/v1"] = "v2"
+	require.Empty(t, changed.restore(loadCache(path, "key"), inputs))
+	require.Empty(t, changed.groupVersions)
+	require.NotContains(t, changed.types, types.Key(thing))
+	require.Contains(t, changed.types, types.Key(str))
+}
+
+func TestProcessCacheInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	writeTypes := func(version, doc string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, version), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, version, "types.go"), []byte(`// +groupName=example.com
+package `+version+`
+
+// `+doc+`
+// +kubebuilder:object:root=true
+type Thing struct {
+	Size int `+"`json:\"size\"`"+`
+}
+`), 0o644))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/api\n\ngo 1.19\n"), 0o644))
+	writeTypes("v1", "Thing is the first version.")
+	writeTypes("v2", "Thing is the second version.")
+
+	conf := &config.Config{Flags: config.Flags{SourcePaths: []string{dir}, MaxDepth: 6, CacheDir: filepath.Join(t.TempDir(), "cache")}}
+	path, err := cachePath(conf)
+	require.NoError(t, err)
+	compiledConfig, err := compileConfig(conf)
+	require.NoError(t, err)
+
+	gvd, err := Process(conf)
+	require.NoError(t, err)
+	require.Len(t, gvd, 2)
+	require.Equal(t, "Thing is the first version.", gvd[0].Types["Thing"].Doc)
+	require.FileExists(t, path)
+
+	// all the packages are reused as long as they do not change
+	inputs, err := readCacheInputs(conf, compiledConfig)
+	require.NoError(t, err)
+	reused := newProcessor(compiledConfig, conf.MaxDepth).restore(loadCache(path, inputs.key), inputs)
+	require.Equal(t, map[string]bool{"example.com/api/v1": true, "example.com/api/v2": true}, reused)
+
+	// only the changed package is loaded again
+	writeTypes("v1", "Thing is the first version, revised.")
+	changedInputs, err := readCacheInputs(conf, compiledConfig)
+	require.NoError(t, err)
+	require.Equal(t, inputs.key, changedInputs.key)
+	require.NotEqual(t, inputs.packages["example.com/api/v1"], changedInputs.packages["example.com/api/v1"])
+	require.Equal(t, inputs.packages["example.com/api/v2"], changedInputs.packages["example.com/api/v2"])
+	reused = newProcessor(compiledConfig, conf.MaxDepth).restore(loadCache(path, changedInputs.key), changedInputs)
+	require.Equal(t, map[string]bool{"example.com/api/v2": true}, reused)
+
+	gvd, err = Process(conf)
+	require.NoError(t, err)
+	require.Len(t, gvd, 2)
+	require.Equal(t, "Thing is the first version, revised.", gvd[0].Types["Thing"].Doc)
+	require.Equal(t, "Thing is the second version.", gvd[1].Types["Thing"].Doc)
+
+	// the processed model does not depend on the reused packages
+	uncached := *conf
+	uncached.CacheDir = ""
+	expected, err := Process(&uncached)
+	require.NoError(t, err)
+	require.Equal(t, expected[1].Types["Thing"].Doc, gvd[1].Types["Thing"].Doc)
+	require.Equal(t, expected[1].Kinds, gvd[1].Kinds)
+	require.Equal(t, expected[1].Types["Thing"].GVK, gvd[1].Types["Thing"].GVK)
+}
@@ -18,11 +18,20 @@ package processor
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/manifest"
+	"github.com/elastic/crd-ref-docs/types"
+	"golang.org/x/tools/go/packages"
 )
 
+// defaultPackages are the patterns of the packages loaded when none are configured.
+var defaultPackages = []string{"./..."}
+
 func compileConfig(conf *config.Config) (cc *compiledConfig, err error) {
 	if conf == nil {
 		return nil, nil
@@ -33,6 +42,40 @@ func compileConfig(conf *config.Config) (cc *compiledConfig, err error) {
 		ignoreFields:        make([]*regexp.Regexp, len(conf.Processor.IgnoreFields)),
 		ignoreGroupVersions: make([]*regexp.Regexp, len(conf.Processor.IgnoreGroupVersions)),
 		useRawDocstring:     conf.Processor.UseRawDocstring,
+		packages:            conf.Processor.Packages,
+		excludeDirs:         conf.Processor.ExcludeDirs,
+		goFlags:             conf.Processor.GoFlags,
+		overlay:             conf.Overlay,
+		kindDetection:       make(map[string]bool),
+	}
+
+	kindDetection := conf.Processor.KindDetection
+	if len(kindDetection) == 0 {
+		kindDetection = []string{objectRootDetection}
+	}
+	for _, strategy := range kindDetection {
+		cc.kindDetection[strategy] = true
+	}
+	for strategy := range cc.kindDetection {
+		if !isKindDetectionStrategy(strategy) {
+			return nil, fmt.Errorf("unknown kind detection strategy '%s', expected one of %v", strategy, kindDetectionStrategies)
+		}
+	}
+
+	if len(cc.packages) == 0 {
+		cc.packages = defaultPackages
+	}
+
+	if len(conf.Processor.BuildTags) > 0 {
+		// the loader sets the ignore_autogenerated tag, which the last -tags flag would override
+		tags := append([]string{"ignore_autogenerated"}, conf.Processor.BuildTags...)
+		cc.buildFlags = []string{"-tags=" + strings.Join(tags, ",")}
+	}
+
+	for _, d := range conf.Processor.ExcludeDirs {
+		if _, err := filepath.Match(d, ""); err != nil {
+			return nil, fmt.Errorf("failed to compile directory pattern '%s': %w", d, err)
+		}
 	}
 
 	for i, t := range conf.Processor.IgnoreTypes {
@@ -61,6 +104,54 @@ type compiledConfig struct {
 	ignoreFields        []*regexp.Regexp
 	ignoreGroupVersions []*regexp.Regexp
 	useRawDocstring     bool
+	packages            []string
+	excludeDirs         []string
+	buildFlags          []string
+	goFlags             []string
+	overlay             map[string][]byte
+	kindDetection       map[string]bool
+}
+
+// packagesConfig returns the configuration to load the packages in the directory with.
+func (cc *compiledConfig) packagesConfig(directory string, mode packages.LoadMode) *packages.Config {
+	cfg := &packages.Config{Dir: directory, Mode: mode}
+	if cc == nil {
+		return cfg
+	}
+
+	cfg.BuildFlags = cc.buildFlags
+	cfg.Overlay = cc.overlay
+	if len(cc.goFlags) > 0 {
+		goFlags := append(strings.Fields(os.Getenv("GOFLAGS")), cc.goFlags...)
+		cfg.Env = append(os.Environ(), "GOFLAGS="+strings.Join(goFlags, " "))
+	}
+	return cfg
+}
+
+// patterns returns the patterns of the packages to load.
+func (cc *compiledConfig) patterns() []string {
+	if cc == nil {
+		return defaultPackages
+	}
+	return cc.packages
+}
+
+// shouldExcludeDir reports whether the packages in dir, a directory relative to the source path,
+// should not be processed.
+func (cc *compiledConfig) shouldExcludeDir(dir string) bool {
+	if cc == nil {
+		return false
+	}
+
+	for d := filepath.ToSlash(dir); d != "." && d != "/" && d != ""; d = filepath.ToSlash(filepath.Dir(d)) {
+		for _, pattern := range cc.excludeDirs {
+			if ok, _ := filepath.Match(filepath.ToSlash(pattern), d); ok {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func (cc *compiledConfig) shouldIgnoreGroupVersion(gv string) bool {
@@ -91,6 +182,18 @@ func (cc *compiledConfig) shouldIgnoreType(fqn string) bool {
 	return false
 }
 
+// IgnoredField returns the function reporting the fields excluded from the types by the
+// ignoreFields option of the configuration.
+func IgnoredField(conf *config.Config) (manifest.IgnoredField, error) {
+	cc, err := compileConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	return func(t *types.Type, name string) bool {
+		return cc.shouldIgnoreField(types.Key(t), name)
+	}, nil
+}
+
 func (cc *compiledConfig) shouldIgnoreField(typeName, fieldName string) bool {
 	if cc == nil {
 		return false
@@ -13,6 +13,9 @@ func TestCompiledConfig(t *testing.T) {
 			IgnoreTypes:         []string{"typex$"},
 			IgnoreFields:        []string{`mytype\.Fieldy$`},
 			IgnoreGroupVersions: []string{"groupz/v1$"},
+			ExcludeDirs:         []string{"hack", "*/fixtures"},
+			BuildTags:           []string{"extra"},
+			GoFlags:             []string{"-mod=vendor"},
 		},
 	}
 
@@ -33,4 +36,25 @@ func TestCompiledConfig(t *testing.T) {
 		require.True(t, cc.shouldIgnoreGroupVersion("groupz/v1"))
 		require.False(t, cc.shouldIgnoreGroupVersion("groupz/v1beta1"))
 	})
+
+	t.Run("excludeDir", func(t *testing.T) {
+		require.True(t, cc.shouldExcludeDir("hack"))
+		require.True(t, cc.shouldExcludeDir("hack/tools"))
+		require.True(t, cc.shouldExcludeDir("api/fixtures/v1"))
+		require.False(t, cc.shouldExcludeDir("api/v1"))
+		require.False(t, cc.shouldExcludeDir("fixtures"))
+	})
+
+	t.Run("packagesConfig", func(t *testing.T) {
+		cfg := cc.packagesConfig("api", 0)
+		require.Equal(t, "api", cfg.Dir)
+		require.Equal(t, []string{"-tags=ignore_autogenerated,extra"}, cfg.BuildFlags)
+		require.Contains(t, cfg.Env[len(cfg.Env)-1], "-mod=vendor")
+		require.Equal(t, []string{"./..."}, cc.patterns())
+	})
+}
+
+func TestCompiledConfigInvalidExcludeDir(t *testing.T) {
+	_, err := compileConfig(&config.Config{Processor: config.ProcessorConfig{ExcludeDirs: []string{"["}}})
+	require.Error(t, err)
 }
@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"go.uber.org/zap"
+
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// Strategies detecting the types of a package that are Kinds.
+const (
+	// objectRootDetection detects types marked with +kubebuilder:object:root.
+	objectRootDetection = "objectRoot"
+	// genclientDetection detects types marked with +genclient, as in code-generator projects.
+	genclientDetection = "genclient"
+	// schemeRegistrationDetection detects types registered with a scheme by the package, with
+	// SchemeBuilder.Register or scheme.AddKnownTypes.
+	schemeRegistrationDetection = "schemeRegistration"
+)
+
+var kindDetectionStrategies = []string{objectRootDetection, genclientDetection, schemeRegistrationDetection}
+
+func isKindDetectionStrategy(strategy string) bool {
+	for _, s := range kindDetectionStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// isKind reports whether the type is a Kind according to the configured detection strategies.
+// registered holds the names of the types registered with a scheme by the package of the type.
+func (cc *compiledConfig) isKind(info *markers.TypeInfo, registered map[string]struct{}) bool {
+	if cc == nil || cc.kindDetection[objectRootDetection] {
+		if info.Markers.Get(objectRootMarker) != nil {
+			return true
+		}
+	}
+
+	if cc == nil {
+		return false
+	}
+
+	if cc.kindDetection[genclientDetection] && info.Markers.Get(genclientMarker) != nil {
+		return true
+	}
+
+	if cc.kindDetection[schemeRegistrationDetection] {
+		if _, ok := registered[info.Name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registeredTypes returns the names of the types of the package that it registers with a scheme,
+// in calls such as SchemeBuilder.Register(&Foo{}, &FooList{}) or
+// scheme.AddKnownTypes(SchemeGroupVersion, &Foo{}, &FooList{}).
+func registeredTypes(pkg *loader.Package) map[string]struct{} {
+	pkg.NeedSyntax()
+
+	registered := make(map[string]struct{})
+	for _, file := range pkg.Syntax {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			var args []ast.Expr
+			switch {
+			case sel.Sel.Name == "Register" && strings.HasSuffix(exprName(sel.X), "SchemeBuilder"):
+				args = call.Args
+			case sel.Sel.Name == "AddKnownTypes" && len(call.Args) > 0:
+				args = call.Args[1:]
+			}
+
+			for _, arg := range args {
+				if name := compositeTypeName(arg); name != "" {
+					registered[name] = struct{}{}
+				}
+			}
+			return true
+		})
+	}
+
+	return registered
+}
+
+// exprName returns the name of an identifier or of the selected field of a selector.
+func exprName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// compositeTypeName returns the name of the local type of a &Foo{} or Foo{} literal.
+func compositeTypeName(expr ast.Expr) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok {
+		expr = unary.X
+	}
+
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return ""
+	}
+
+	if ident, ok := lit.Type.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// collapseListKinds removes the List Kinds of the group version, and sets the ListKind of the
+// Kinds they list.
+func collapseListKinds(gv *types.GroupVersionDetails) {
+	kinds := gv.Kinds[:0]
+	for _, kind := range gv.Kinds {
+		list := gv.Types[kind]
+		item := listedKind(gv, list)
+		if item == nil {
+			kinds = append(kinds, kind)
+			continue
+		}
+
+		zap.S().Debugw("Collapsing List Kind", "kind", kind, "itemKind", item.Name)
+		item.ListKind = kind
+		delete(gv.Types, kind)
+
+		references := item.References[:0]
+		for _, ref := range item.References {
+			if ref != list {
+				references = append(references, ref)
+			}
+		}
+		item.References = references
+	}
+	gv.Kinds = kinds
+}
+
+// listedKind returns the Kind of the items of a List Kind, or nil when t is not a List Kind. List
+// Kinds have list metadata and a list of items of another Kind of the group version.
+func listedKind(gv *types.GroupVersionDetails, t *types.Type) *types.Type {
+	if t == nil || t.Kind != types.StructKind {
+		return nil
+	}
+
+	var hasListMeta bool
+	var item *types.Type
+	for _, f := range t.Fields {
+		switch f.Name {
+		case "metadata":
+			hasListMeta = f.Type != nil && f.Type.Name == "ListMeta" && f.Type.Package == "k8s.io/apimachinery/pkg/apis/meta/v1"
+		case "items":
+			if f.Type != nil && f.Type.Kind == types.SliceKind {
+				item = f.Type.UnderlyingType
+				if item != nil && item.Kind == types.PointerKind {
+					item = item.UnderlyingType
+				}
+			}
+		}
+	}
+
+	if !hasListMeta || item == nil || item.GVK == nil || item == t || gv.Types[item.Name] != item {
+		return nil
+	}
+	return item
+}
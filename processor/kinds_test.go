@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestKindDetection(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/api\n\ngo 1.19\n",
+		"v1/doc.go": `// Package v1 is a code-generator style API.
+// +groupName=example.com
+package v1
+`,
+		"v1/register.go": `package v1
+
+type schemeBuilder []func(*scheme) error
+
+func (sb *schemeBuilder) Register(objs ...interface{}) {}
+
+type scheme struct{}
+
+func (s *scheme) AddKnownTypes(gv string, objs ...interface{}) {}
+
+var SchemeBuilder = &schemeBuilder{}
+
+func init() {
+	SchemeBuilder.Register(&Registered{})
+}
+
+func addKnownTypes(s *scheme) error {
+	s.AddKnownTypes("example.com/v1", &Known{}, &KnownList{})
+	return nil
+}
+`,
+		"v1/types.go": `package v1
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:method=GetScale,verb=get,subresource=scale,result=k8s.io/api/autoscaling/v1.Scale
+
+// Client is marked with genclient.
+type Client struct{}
+
+// Root is marked as a root object.
+// +kubebuilder:object:root=true
+type Root struct{}
+
+// Registered is registered with the scheme builder.
+type Registered struct{}
+
+// Known is added to the scheme.
+type Known struct{}
+
+// KnownList is a list of Known.
+type KnownList struct {
+	Items []Known ` + "`json:\"items\"`" + `
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	testCases := []struct {
+		strategies []string
+		want       []string
+	}{
+		{strategies: nil, want: []string{"Root"}},
+		{strategies: []string{genclientDetection}, want: []string{"Client"}},
+		{strategies: []string{schemeRegistrationDetection}, want: []string{"Known", "KnownList", "Registered"}},
+		{strategies: kindDetectionStrategies, want: []string{"Client", "Known", "KnownList", "Registered", "Root"}},
+	}
+
+	for _, tc := range testCases {
+		conf := &config.Config{
+			Processor: config.ProcessorConfig{KindDetection: tc.strategies},
+			Flags:     config.Flags{SourcePaths: []string{dir}, MaxDepth: 6},
+		}
+
+		gvd, err := Process(conf)
+		require.NoError(t, err)
+		require.Len(t, gvd, 1)
+		require.Equal(t, tc.want, gvd[0].SortedKinds(), "strategies %v", tc.strategies)
+		for _, kind := range tc.want {
+			require.Equal(t, "example.com", gvd[0].TypeForKind(kind).GVK.Group)
+		}
+	}
+}
+
+func TestUnknownKindDetection(t *testing.T) {
+	_, err := compileConfig(&config.Config{Processor: config.ProcessorConfig{KindDetection: []string{"magic"}}})
+	require.Error(t, err)
+}
+
+func TestCollapseListKinds(t *testing.T) {
+	listMeta := &types.Type{Name: "ListMeta", Package: "k8s.io/apimachinery/pkg/apis/meta/v1", Kind: types.StructKind}
+	gvk := func(kind string) *schema.GroupVersionKind {
+		return &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: kind}
+	}
+
+	thing := &types.Type{Name: "Thing", Kind: types.StructKind, GVK: gvk("Thing")}
+	thingList := &types.Type{
+		Name: "ThingList",
+		Kind: types.StructKind,
+		GVK:  gvk("ThingList"),
+		Fields: types.Fields{
+			{Name: "metadata", Type: listMeta},
+			{Name: "items", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: thing}},
+		},
+	}
+	thing.References = []*types.Type{thingList}
+
+	// not a List Kind: the items are not of a Kind
+	part := &types.Type{Name: "Part", Kind: types.StructKind}
+	partList := &types.Type{
+		Name: "PartList",
+		Kind: types.StructKind,
+		GVK:  gvk("PartList"),
+		Fields: types.Fields{
+			{Name: "metadata", Type: listMeta},
+			{Name: "items", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: part}},
+		},
+	}
+
+	gv := types.GroupVersionDetails{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing", "ThingList", "PartList"},
+		Types:        types.TypeMap{"Thing": thing, "ThingList": thingList, "Part": part, "PartList": partList},
+	}
+	collapseListKinds(&gv)
+
+	require.Equal(t, []string{"Thing", "PartList"}, gv.Kinds)
+	require.NotContains(t, gv.Types, "ThingList")
+	require.Equal(t, "ThingList", thing.ListKind)
+	require.Empty(t, thing.References)
+	require.Empty(t, part.ListKind)
+}
@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+
+	"sigs.k8s.io/controller-tools/pkg/loader"
+)
+
+// applyOverlay parses the packages with files replaced by the overlay. The loader reads the files
+// of packages from disk when it parses them, so the syntax of these packages is set beforehand.
+func applyOverlay(fset *token.FileSet, roots []*loader.Package, overlay map[string][]byte) {
+	if len(overlay) == 0 {
+		return
+	}
+
+	visited := make(map[*loader.Package]struct{})
+	queue := append([]*loader.Package(nil), roots...)
+packages:
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[pkg]; ok {
+			continue
+		}
+		visited[pkg] = struct{}{}
+
+		for _, imp := range pkg.Imports() {
+			queue = append(queue, imp)
+		}
+
+		if pkg.Syntax != nil || !hasOverlay(pkg, overlay) {
+			continue
+		}
+
+		syntax := make([]*ast.File, 0, len(pkg.CompiledGoFiles))
+		for _, filename := range pkg.CompiledGoFiles {
+			src, err := readFile(filename, overlay)
+			if err != nil {
+				pkg.AddError(err)
+				continue packages
+			}
+			file, err := parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+			if err != nil {
+				pkg.AddError(err)
+				continue packages
+			}
+			syntax = append(syntax, file)
+		}
+		pkg.Syntax = syntax
+	}
+}
+
+func hasOverlay(pkg *loader.Package, overlay map[string][]byte) bool {
+	for _, filename := range pkg.CompiledGoFiles {
+		if _, ok := overlay[filename]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readFile returns the content of the file in the overlay, or on disk.
+func readFile(filename string, overlay map[string][]byte) ([]byte, error) {
+	if src, ok := overlay[filename]; ok {
+		return src, nil
+	}
+	return os.ReadFile(filename)
+}
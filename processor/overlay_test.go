@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	writeFile("go.mod", "module example.com/api\n\ngo 1.19\n")
+	types := writeFile("v1/types.go", `// +groupName=example.com
+package v1
+
+// Thing is saved on disk.
+// +kubebuilder:object:root=true
+type Thing struct {
+	Size int `+"`json:\"size\"`"+`
+}
+`)
+
+	conf := &config.Config{
+		Flags: config.Flags{SourcePaths: []string{dir}, MaxDepth: 6},
+		Overlay: map[string][]byte{
+			types: []byte(`// +groupName=example.com
+package v1
+
+// Thing is not saved yet.
+// +kubebuilder:object:root=true
+type Thing struct {
+	Size int ` + "`json:\"size\"`" + `
+}
+`),
+			filepath.Join(dir, "v1", "other.go"): []byte(`package v1
+
+// Other only exists in the overlay.
+// +kubebuilder:object:root=true
+type Other struct{}
+`),
+		},
+	}
+
+	gvd, err := Process(conf)
+	require.NoError(t, err)
+	require.Len(t, gvd, 1)
+	require.ElementsMatch(t, []string{"Thing", "Other"}, gvd[0].Kinds)
+	require.Equal(t, "Thing is not saved yet.", gvd[0].Types["Thing"].Doc)
+}
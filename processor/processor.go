@@ -19,24 +19,32 @@ package processor
 import (
 	"fmt"
 	gotypes "go/types"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/manifest"
 	"github.com/elastic/crd-ref-docs/types"
 	"go.uber.org/zap"
-	"golang.org/x/tools/go/packages"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-tools/pkg/crd"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
 	"sigs.k8s.io/controller-tools/pkg/loader"
 	"sigs.k8s.io/controller-tools/pkg/markers"
 )
 
 const (
-	groupNameMarker   = "groupName"
-	objectRootMarker  = "kubebuilder:object:root"
-	versionNameMarker = "versionName"
+	defaultMarker            = "kubebuilder:default"
+	genclientMarker          = "genclient"
+	groupNameMarker          = "groupName"
+	objectRootMarker         = "kubebuilder:object:root"
+	optionalMarker           = "optional"
+	requiredMarker           = "kubebuilder:validation:Required"
+	validationMarkerPrefix   = "kubebuilder:validation:"
+	validationOptionalMarker = "kubebuilder:validation:Optional"
+	versionNameMarker        = "versionName"
 )
 
 var ignoredCommentRegex = regexp.MustCompile(`\s*^(?i:\+|copyright)`)
@@ -49,7 +57,80 @@ type groupVersionInfo struct {
 	types types.TypeMap
 }
 
+// apiPackage is the group version declared by a package.
+type apiPackage struct {
+	schema.GroupVersion
+	doc string
+}
+
 func Process(config *config.Config) ([]types.GroupVersionDetails, error) {
+	gvDetails, err := processCached(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Processor.SamplesPath != "" {
+		ignored, err := IgnoredField(config)
+		if err != nil {
+			return nil, err
+		}
+		if err := attachSamples(config.Processor.SamplesPath, gvDetails, ignored); err != nil {
+			return nil, fmt.Errorf("failed to load samples from %s: %w", config.Processor.SamplesPath, err)
+		}
+	}
+
+	return gvDetails, nil
+}
+
+// processCached processes the source paths, reusing the types cached in the cache directory for
+// the packages that did not change since they were cached, and stores the types in the cache.
+func processCached(config *config.Config) ([]types.GroupVersionDetails, error) {
+	if config.CacheDir == "" {
+		return process(config)
+	}
+
+	compiledConfig, err := compileConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := readCacheInputs(config, compiledConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute cache keys of directories %v: %w", config.SourcePaths, err)
+	}
+	path, err := cachePath(config)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newProcessor(compiledConfig, config.Flags.MaxDepth)
+	reused := p.restore(loadCache(path, inputs.key), inputs)
+	if len(reused) > 0 {
+		zap.S().Debugw("Using cached types", "path", path, "packages", len(reused))
+	}
+	for _, directory := range config.SourcePaths {
+		var changed []string
+		for _, pkg := range inputs.roots[directory] {
+			if !reused[pkg] {
+				changed = append(changed, pkg)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		zap.S().Debugw("Loading changed packages", "directory", directory, "changedPackages", changed)
+		if err := p.findAPITypes(directory, changed); err != nil {
+			return nil, fmt.Errorf("failed to find API types in directory %s:%w", directory, err)
+		}
+	}
+
+	if err := storeCache(path, p.snapshot(inputs)); err != nil {
+		zap.S().Warnw("Failed to write cache", "path", path, "error", err)
+	}
+	return p.groupVersionDetails(config)
+}
+
+func process(config *config.Config) ([]types.GroupVersionDetails, error) {
 	compiledConfig, err := compileConfig(config)
 	if err != nil {
 		return nil, err
@@ -57,10 +138,18 @@ func Process(config *config.Config) ([]types.GroupVersionDetails, error) {
 
 	p := newProcessor(compiledConfig, config.Flags.MaxDepth)
 	// locate the packages annotated with group names
-	if err := p.findAPITypes(config.SourcePath); err != nil {
-		return nil, fmt.Errorf("failed to find API types in directory %s:%w", config.SourcePath, err)
+	for _, directory := range config.SourcePaths {
+		if err := p.findAPITypes(directory, nil); err != nil {
+			return nil, fmt.Errorf("failed to find API types in directory %s:%w", directory, err)
+		}
 	}
 
+	return p.groupVersionDetails(config)
+}
+
+// groupVersionDetails completes the types found in the source paths and returns them by group
+// version.
+func (p *processor) groupVersionDetails(config *config.Config) ([]types.GroupVersionDetails, error) {
 	p.types.InlineTypes(p.propagateReference)
 
 	// collect references between types
@@ -100,6 +189,10 @@ func Process(config *config.Config) ([]types.GroupVersionDetails, error) {
 			}
 		}
 
+		if config.Processor.CollapseListKinds {
+			collapseListKinds(&details)
+		}
+
 		gvDetails = append(gvDetails, details)
 	}
 
@@ -116,20 +209,53 @@ func Process(config *config.Config) ([]types.GroupVersionDetails, error) {
 		return false
 	})
 
+	collectFieldReferences(gvDetails, config.MaxDepth)
+
 	return gvDetails, nil
 }
 
+// attachSamples adds the sample manifests found at the given path to the types of their Kinds,
+// warning about samples that do not match any Kind or that use fields unknown to the API.
+func attachSamples(path string, gvDetails []types.GroupVersionDetails, ignored manifest.IgnoredField) error {
+	docs, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		t := manifest.FindKind(gvDetails, doc.APIVersion, doc.Kind)
+		if t == nil {
+			zap.S().Warnw("Sample does not match any Kind", "path", doc.Path, "line", doc.Line, "apiVersion", doc.APIVersion, "kind", doc.Kind)
+			continue
+		}
+
+		for _, problem := range manifest.Check(doc, t, ignored) {
+			zap.S().Warnw("Sample does not match API", "problem", problem.String())
+		}
+
+		source, err := filepath.Rel(path, doc.Path)
+		if err != nil || source == "." {
+			source = filepath.Base(doc.Path)
+		}
+		t.Examples = append(t.Examples, types.Example{Source: filepath.ToSlash(source), Content: doc.Content})
+	}
+
+	return nil
+}
+
 func newProcessor(compiledConfig *compiledConfig, maxDepth int) *processor {
 	p := &processor{
 		compiledConfig: compiledConfig,
 		maxDepth:       maxDepth,
 		parser: &crd.Parser{
-			Collector: &markers.Collector{Registry: &markers.Registry{}},
+			Collector: &markers.Collector{Registry: mkRegistry()},
 			Checker:   &loader.TypeChecker{},
 		},
-		groupVersions: make(map[schema.GroupVersion]*groupVersionInfo),
-		types:         make(types.TypeMap),
-		references:    make(map[string]map[string]struct{}),
+		groupVersions:  make(map[schema.GroupVersion]*groupVersionInfo),
+		types:          make(types.TypeMap),
+		references:     make(map[string]map[string]struct{}),
+		loadedPackages: make(map[string]struct{}),
+		apiPackages:    make(map[string]apiPackage),
 	}
 
 	crd.AddKnownTypes(p.parser)
@@ -143,17 +269,50 @@ type processor struct {
 	groupVersions map[schema.GroupVersion]*groupVersionInfo
 	types         types.TypeMap
 	references    map[string]map[string]struct{}
+	// loadedPackages are the import paths of the packages already searched for API types, as
+	// source paths may share packages.
+	loadedPackages map[string]struct{}
+	// apiPackages are the group versions declared by the packages holding API types, by import
+	// path.
+	apiPackages map[string]apiPackage
 }
 
-func (p *processor) findAPITypes(directory string) error {
-	cfg := &packages.Config{Dir: directory}
-	pkgs, err := loader.LoadRootsWithConfig(cfg, "./...")
+// findAPITypes loads the packages of the directory matched by the patterns, or by the configured
+// patterns if nil, and adds the API types they hold.
+func (p *processor) findAPITypes(directory string, patterns []string) error {
+	if patterns == nil {
+		var err error
+		if patterns, err = workspacePatterns(directory, p.patterns()); err != nil {
+			return err
+		}
+	}
+
+	cfg := p.packagesConfig(directory, 0)
+	pkgs, err := loader.LoadRootsWithConfig(cfg, patterns...)
+	if err != nil {
+		return err
+	}
+	applyOverlay(cfg.Fset, pkgs, p.overlay)
+
+	absDirectory, err := filepath.Abs(directory)
 	if err != nil {
 		return err
 	}
 
 	collector := &markers.Collector{Registry: mkRegistry()}
 	for _, pkg := range pkgs {
+		if _, ok := p.loadedPackages[pkg.PkgPath]; ok {
+			continue
+		}
+		p.loadedPackages[pkg.PkgPath] = struct{}{}
+
+		if len(pkg.GoFiles) > 0 {
+			if dir, err := filepath.Rel(absDirectory, filepath.Dir(pkg.GoFiles[0])); err == nil && p.shouldExcludeDir(dir) {
+				zap.S().Debugw("Skipping excluded package", "package", pkg.PkgPath)
+				continue
+			}
+		}
+
 		gvInfo := p.extractGroupVersionIfExists(collector, pkg)
 		if gvInfo == nil {
 			continue
@@ -166,15 +325,12 @@ func (p *processor) findAPITypes(directory string) error {
 		// let the parser know that we need this package
 		p.parser.AddPackage(pkg)
 
-		// if we have encountered this GV before, use that instead
-		if gv, ok := p.groupVersions[gvInfo.GroupVersion]; ok {
-			gvInfo = gv
-		} else {
-			p.groupVersions[gvInfo.GroupVersion] = gvInfo
-		}
+		p.apiPackages[pkg.PkgPath] = apiPackage{GroupVersion: gvInfo.GroupVersion, doc: gvInfo.doc}
+		gvInfo = p.addGroupVersion(gvInfo)
 
-		if gvInfo.types == nil {
-			gvInfo.types = make(types.TypeMap)
+		var registered map[string]struct{}
+		if p.kindDetection[schemeRegistrationDetection] {
+			registered = registeredTypes(pkg)
 		}
 
 		// locate the kinds
@@ -202,10 +358,7 @@ func (p *processor) findAPITypes(directory string) error {
 			}
 
 			// is this a root object?
-			if root := info.Markers.Get(objectRootMarker); root != nil {
-				if gvInfo.kinds == nil {
-					gvInfo.kinds = make(map[string]struct{})
-				}
+			if p.isKind(info, registered) {
 				gvInfo.kinds[info.Name] = struct{}{}
 				typeDef.GVK = &schema.GroupVersionKind{Group: gvInfo.Group, Version: gvInfo.Version, Kind: info.Name}
 			}
@@ -216,6 +369,19 @@ func (p *processor) findAPITypes(directory string) error {
 	return nil
 }
 
+// addGroupVersion registers the group version, unless it was encountered before, and returns the
+// registered one.
+func (p *processor) addGroupVersion(gvInfo *groupVersionInfo) *groupVersionInfo {
+	if gv, ok := p.groupVersions[gvInfo.GroupVersion]; ok {
+		return gv
+	}
+
+	gvInfo.kinds = make(map[string]struct{})
+	gvInfo.types = make(types.TypeMap)
+	p.groupVersions[gvInfo.GroupVersion] = gvInfo
+	return gvInfo
+}
+
 func (p *processor) extractGroupVersionIfExists(collector *markers.Collector, pkg *loader.Package) *groupVersionInfo {
 	markerValues, err := markers.PackageMarkers(collector, pkg)
 	if err != nil {
@@ -267,9 +433,11 @@ func (p *processor) extractPkgDocumentation(pkg *loader.Package) string {
 
 func (p *processor) processType(pkg *loader.Package, info *markers.TypeInfo, depth int) *types.Type {
 	typeDef := &types.Type{
-		Name:    info.Name,
-		Package: pkg.PkgPath,
-		Doc:     info.Doc,
+		Name:       info.Name,
+		Package:    pkg.PkgPath,
+		Doc:        info.Doc,
+		Markers:    info.Markers,
+		Validation: validationRules(info.Markers),
 	}
 
 	if p.useRawDocstring && info.RawDecl != nil {
@@ -299,6 +467,8 @@ func (p *processor) processType(pkg *loader.Package, info *markers.TypeInfo, dep
 	tmpType.Name = typeDef.Name
 	tmpType.Package = typeDef.Package
 	tmpType.Doc = typeDef.Doc
+	tmpType.Markers = typeDef.Markers
+	tmpType.Validation = typeDef.Validation
 	return tmpType
 }
 
@@ -315,9 +485,16 @@ func (p *processor) processStructFields(parentType *types.Type, pkg *loader.Pack
 		}
 
 		fieldDef := &types.Field{
-			Name:     f.Name,
-			Doc:      f.Doc,
-			Embedded: f.Name == "",
+			Name:       f.Name,
+			GoName:     f.Name,
+			Doc:        f.Doc,
+			Embedded:   f.Name == "",
+			Markers:    f.Markers,
+			Validation: validationRules(f.Markers),
+		}
+
+		if d, ok := f.Markers.Get(defaultMarker).(crdmarkers.Default); ok {
+			fieldDef.Default = d.Value
 		}
 
 		if tagVal, ok := f.Tag.Lookup("json"); ok {
@@ -325,6 +502,16 @@ func (p *processor) processStructFields(parentType *types.Type, pkg *loader.Pack
 			if len(args) > 0 && args[0] != "" {
 				fieldDef.Name = args[0]
 			}
+			for _, arg := range args[1:] {
+				fieldDef.Optional = fieldDef.Optional || arg == "omitempty"
+			}
+		}
+
+		switch {
+		case f.Markers.Get(requiredMarker) != nil:
+			fieldDef.Optional = false
+		case f.Markers.Get(optionalMarker) != nil, f.Markers.Get(validationOptionalMarker) != nil:
+			fieldDef.Optional = true
 		}
 
 		logger.Debugw("Loading field type", "field", fieldDef.Name)
@@ -484,6 +671,8 @@ func (p *processor) loadAliasType(typeDef *types.Type, pkg *loader.Package, unde
 	if bt, ok := underlying.(*gotypes.Basic); ok {
 		typeDef.UnderlyingType = &types.Type{Name: bt.String(), Kind: types.BasicKind}
 		typeDef.Doc = tInfo.Doc
+		typeDef.Markers = tInfo.Markers
+		typeDef.Validation = validationRules(tInfo.Markers)
 		return typeDef
 	}
 
@@ -531,10 +720,45 @@ func (p *processor) addReference(parent *types.Type, child *types.Type) {
 	}
 }
 
+// validationRules returns a human-readable description of the validation markers among the given markers.
+func validationRules(values markers.MarkerValues) []string {
+	var rules []string
+	for name, vals := range values {
+		if !strings.HasPrefix(name, validationMarkerPrefix) {
+			continue
+		}
+
+		rule := strings.TrimPrefix(name, validationMarkerPrefix)
+		for _, v := range vals {
+			switch x := v.(type) {
+			case struct{}, crdmarkers.XEmbeddedResource, crdmarkers.XIntOrString, crdmarkers.XPreserveUnknownFields:
+				rules = append(rules, rule)
+			case crdmarkers.XValidation:
+				rules = append(rules, fmt.Sprintf("%s: %s", rule, x.Rule))
+			default:
+				rules = append(rules, fmt.Sprintf("%s: %v", rule, x))
+			}
+		}
+	}
+
+	sort.Strings(rules)
+	return rules
+}
+
 func mkRegistry() *markers.Registry {
 	registry := &markers.Registry{}
+	if err := crdmarkers.Register(registry); err != nil {
+		zap.S().Fatalw("Failed to register CRD markers", "error", err)
+	}
 	registry.Define(groupNameMarker, markers.DescribesPackage, "")
 	registry.Define(objectRootMarker, markers.DescribesType, true)
+	// +genclient is used both bare and with arguments, as in +genclient:method=..., which are
+	// kept unparsed
+	genclient := markers.Must(markers.MakeDefinition(genclientMarker, markers.DescribesType, markers.RawArguments("")))
+	genclient.Strict = false
+	if err := registry.Register(genclient); err != nil {
+		zap.S().Fatalw("Failed to register genclient marker", "error", err)
+	}
 	registry.Define(versionNameMarker, markers.DescribesPackage, "")
 	return registry
 }
@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"sort"
+
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// collectFieldReferences sets the fields referring to every type of the group versions, along
+// with the paths of these fields from the Kinds, up to maxDepth fields deep.
+func collectFieldReferences(gvDetails []types.GroupVersionDetails, maxDepth int) {
+	type fieldKey struct {
+		parent *types.Type
+		field  string
+	}
+
+	documented := make(map[*types.Type]struct{})
+	for _, gv := range gvDetails {
+		for _, t := range gv.Types {
+			documented[t] = struct{}{}
+		}
+	}
+
+	refs := make(map[*types.Type]map[fieldKey]*types.FieldReference)
+	reference := func(parent *types.Type, f *types.Field, target *types.Type) *types.FieldReference {
+		if refs[target] == nil {
+			refs[target] = make(map[fieldKey]*types.FieldReference)
+		}
+		key := fieldKey{parent: parent, field: f.Name}
+		if ref, ok := refs[target][key]; ok {
+			return ref
+		}
+		ref := &types.FieldReference{Type: parent, Field: f.Name}
+		refs[target][key] = ref
+		return ref
+	}
+
+	// record the fields of the documented types
+	for t := range documented {
+		for _, f := range t.Members() {
+			if target, _ := fieldTarget(f.Type); target != nil {
+				if _, ok := documented[target]; ok {
+					reference(t, f, target)
+				}
+			}
+		}
+	}
+
+	// record the paths of the fields from the Kinds
+	var walk func(t *types.Type, path string, depth int, onPath map[*types.Type]struct{})
+	walk = func(t *types.Type, path string, depth int, onPath map[*types.Type]struct{}) {
+		if depth >= maxDepth {
+			return
+		}
+		onPath[t] = struct{}{}
+		defer delete(onPath, t)
+
+		for _, f := range t.Members() {
+			target, suffix := fieldTarget(f.Type)
+			if target == nil {
+				continue
+			}
+
+			fieldPath := path + "." + f.Name + suffix
+			if _, ok := documented[target]; ok {
+				ref := reference(t, f, target)
+				ref.Paths = append(ref.Paths, fieldPath)
+			}
+			if _, ok := onPath[target]; !ok && target.Kind == types.StructKind {
+				walk(target, fieldPath, depth+1, onPath)
+			}
+		}
+	}
+
+	for i := range gvDetails {
+		gv := &gvDetails[i]
+		for _, kind := range gv.SortedKinds() {
+			// the paths from List Kinds only repeat the paths from the Kinds they list
+			if t := gv.TypeForKind(kind); t != nil && listedKind(gv, t) == nil {
+				walk(t, kind, 0, make(map[*types.Type]struct{}))
+			}
+		}
+	}
+
+	for target, fieldRefs := range refs {
+		target.FieldReferences = make([]types.FieldReference, 0, len(fieldRefs))
+		for _, ref := range fieldRefs {
+			ref.Paths = sortedUnique(ref.Paths)
+			target.FieldReferences = append(target.FieldReferences, *ref)
+		}
+		sort.Slice(target.FieldReferences, func(i, j int) bool {
+			a, b := target.FieldReferences[i], target.FieldReferences[j]
+			if a.Type.Name != b.Type.Name {
+				return a.Type.Name < b.Type.Name
+			}
+			if a.Type.Package != b.Type.Package {
+				return a.Type.Package < b.Type.Package
+			}
+			return a.Field < b.Field
+		})
+	}
+}
+
+// fieldTarget returns the named type that a field of type t refers to, through pointers, slices,
+// arrays and maps, along with the suffix denoting the elements of slices, arrays and maps in
+// field paths.
+func fieldTarget(t *types.Type) (*types.Type, string) {
+	var suffix string
+	for t != nil {
+		switch t.Kind {
+		case types.PointerKind:
+			t = t.UnderlyingType
+		case types.SliceKind, types.ArrayKind:
+			suffix += "[]"
+			t = t.UnderlyingType
+		case types.MapKind:
+			suffix += "{}"
+			t = t.ValueType
+		default:
+			return t, suffix
+		}
+	}
+	return nil, suffix
+}
+
+func sortedUnique(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
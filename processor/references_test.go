@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCollectFieldReferences(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	rating := &types.Type{Name: "Rating", Kind: types.AliasKind, UnderlyingType: str}
+	entry := &types.Type{
+		Name: "Entry",
+		Kind: types.StructKind,
+		Fields: types.Fields{
+			{Name: "rating", Type: rating},
+			{Name: "ratings", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: rating}},
+		},
+	}
+	node := &types.Type{Name: "Node", Kind: types.StructKind}
+	node.Fields = types.Fields{{Name: "children", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: node}}}
+	spec := &types.Type{
+		Name: "BookSpec",
+		Kind: types.StructKind,
+		Fields: types.Fields{
+			{Name: "entries", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Kind: types.PointerKind, UnderlyingType: entry}}},
+			{Name: "latest", Type: &types.Type{Kind: types.PointerKind, UnderlyingType: entry}},
+			{Name: "tree", Type: node},
+		},
+	}
+	book := &types.Type{
+		Name:   "Book",
+		Kind:   types.StructKind,
+		GVK:    &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Book"},
+		Fields: types.Fields{{Name: "spec", Type: spec}},
+	}
+
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Book"},
+		Types:        types.TypeMap{"Book": book, "BookSpec": spec, "Entry": entry, "Rating": rating, "Node": node},
+	}}
+	collectFieldReferences(gvd, 4)
+
+	describe := func(t *types.Type) []string {
+		var refs []string
+		for _, ref := range t.FieldReferences {
+			refs = append(refs, fmt.Sprintf("%s.%s: %s", ref.Type.Name, ref.Field, strings.Join(ref.Paths, ", ")))
+		}
+		return refs
+	}
+
+	require.Equal(t, []string{
+		"Entry.rating: Book.spec.entries[].rating, Book.spec.latest.rating",
+		"Entry.ratings: Book.spec.entries[].ratings{}, Book.spec.latest.ratings{}",
+	}, describe(rating))
+	require.Equal(t, []string{
+		"BookSpec.entries: Book.spec.entries[]",
+		"BookSpec.latest: Book.spec.latest",
+	}, describe(entry))
+	// recursive types are only walked once along a path
+	require.Equal(t, []string{
+		"BookSpec.tree: Book.spec.tree",
+		"Node.children: Book.spec.tree.children[]",
+	}, describe(node))
+	require.Empty(t, describe(book))
+}
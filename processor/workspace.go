@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// workspacePatterns returns the patterns of the packages to load from the directory. The go
+// command does not match the packages of a workspace against patterns relative to the root of
+// the workspace, so when the directory holds a go.work file but no go.mod file, relative patterns
+// are expanded to the directories of every module used by the workspace.
+func workspacePatterns(directory string, patterns []string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(directory, "go.mod")); err == nil {
+		return patterns, nil
+	}
+
+	workFile := filepath.Join(directory, "go.work")
+	data, err := os.ReadFile(workFile)
+	if os.IsNotExist(err) {
+		return patterns, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := modfile.ParseWork(workFile, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var expanded []string
+	for _, pattern := range patterns {
+		if !strings.HasPrefix(pattern, ".") {
+			expanded = append(expanded, pattern)
+			continue
+		}
+
+		for _, use := range work.Use {
+			dir := use.Path
+			if filepath.IsAbs(dir) {
+				if dir, err = filepath.Rel(directory, dir); err != nil {
+					return nil, err
+				}
+			}
+			expanded = append(expanded, "./"+filepath.ToSlash(filepath.Join(dir, pattern)))
+		}
+	}
+	return expanded, nil
+}
+
+// findWorkFile returns the path of the go.work file used by the go command in the directory, if
+// any.
+func findWorkFile(directory string) (string, error) {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return "", nil
+	case "":
+	default:
+		return gowork, nil
+	}
+
+	dir, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+	for {
+		workFile := filepath.Join(dir, "go.work")
+		if _, err := os.Stat(workFile); err == nil {
+			return workFile, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
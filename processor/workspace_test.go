@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspacePatterns(t *testing.T) {
+	dir := t.TempDir()
+	work := "go 1.19\n\nuse (\n\t./core\n\t./addons\n)\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.work"), []byte(work), 0o644))
+
+	patterns, err := workspacePatterns(dir, []string{"./...", "./api/...", "example.com/other/api"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"./core/...",
+		"./addons/...",
+		"./core/api/...",
+		"./addons/api/...",
+		"example.com/other/api",
+	}, patterns)
+
+	// the patterns are relative to the module when the directory holds one
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/root\n"), 0o644))
+	patterns, err = workspacePatterns(dir, []string{"./..."})
+	require.NoError(t, err)
+	require.Equal(t, []string{"./..."}, patterns)
+}
+
+func TestProcessMultipleModules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	// each module documents a group whose Kind refers to a type of the other module
+	writeFile("core/go.mod", "module example.com/core\n\ngo 1.19\n\nrequire example.com/addons v0.0.0\n\nreplace example.com/addons => ../addons\n")
+	writeFile("core/shared/shared.go", "package shared\n\n// Labels are shared by the Kinds of both modules.\ntype Labels map[string]string\n")
+	writeFile("core/v1/types.go", `// +groupName=core.example.com
+package v1
+
+import "example.com/addons/shared"
+
+// Engine is a core Kind.
+// +kubebuilder:object:root=true
+type Engine struct {
+	Plugin shared.Plugin `+"`json:\"plugin\"`"+`
+}
+`)
+	writeFile("addons/go.mod", "module example.com/addons\n\ngo 1.19\n\nrequire example.com/core v0.0.0\n\nreplace example.com/core => ../core\n")
+	writeFile("addons/shared/shared.go", "package shared\n\n// Plugin is an addon plugged into the Kinds of both modules.\ntype Plugin struct {\n\tName string `json:\"name\"`\n}\n")
+	writeFile("addons/v1/types.go", `// +groupName=addons.example.com
+package v1
+
+import "example.com/core/shared"
+
+// Addon is an addon Kind.
+// +kubebuilder:object:root=true
+type Addon struct {
+	Labels shared.Labels `+"`json:\"labels\"`"+`
+}
+`)
+
+	// the packages of the last source path were already loaded from the first one
+	conf := &config.Config{Flags: config.Flags{
+		SourcePaths: []string{filepath.Join(dir, "core"), filepath.Join(dir, "addons"), filepath.Join(dir, "core", "v1")},
+		MaxDepth:    6,
+	}}
+	gvd, err := Process(conf)
+	require.NoError(t, err)
+	require.Len(t, gvd, 2)
+
+	byGroup := make(map[string][]string)
+	for _, gv := range gvd {
+		byGroup[gv.Group] = gv.Kinds
+	}
+	require.Equal(t, map[string][]string{"core.example.com": {"Engine"}, "addons.example.com": {"Addon"}}, byGroup)
+
+	// the packages loaded again are skipped rather than processed twice
+	conf.SourcePaths = conf.SourcePaths[:2]
+	withoutOverlap, err := Process(conf)
+	require.NoError(t, err)
+	require.Equal(t, withoutOverlap, gvd)
+
+	for _, gv := range gvd {
+		switch gv.Group {
+		case "core.example.com":
+			plugin := gv.Types["Engine"].Fields[0].Type
+			require.Equal(t, "example.com/addons/shared", plugin.Package)
+			require.Equal(t, "Plugin", plugin.Name)
+		case "addons.example.com":
+			labels := gv.Types["Addon"].Fields[0].Type
+			require.Equal(t, "example.com/core/shared", labels.Package)
+			require.Equal(t, "Labels", labels.Name)
+		}
+	}
+}
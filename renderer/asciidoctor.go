@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -30,12 +31,23 @@ import (
 )
 
 const (
-	asciidocAnchorPrefix = "{anchor_prefix}-"
+	asciidocAnchorPrefix        = "{anchor_prefix}-"
+	asciidocDefaultAnchorPrefix = "k8s-api"
+
+	antoraDefaultModule = "ROOT"
+	antoraIndexPage     = "index.adoc"
+	antoraNavFile       = "nav.adoc"
+	antoraIndexTemplate = "antoraIndex"
+	antoraPageTemplate  = "antoraPage"
+	antoraNavTemplate   = "antoraNav"
 )
 
 type AsciidoctorRenderer struct {
 	conf *config.Config
+	fileWriter
 	*Functions
+	// pages maps anchor IDs to the Antora page that defines them
+	pages map[string]string
 }
 
 func NewAsciidoctorRenderer(conf *config.Config) (*AsciidoctorRenderer, error) {
@@ -65,15 +77,63 @@ func (adr *AsciidoctorRenderer) Render(gvd []types.GroupVersionDetails) error {
 		return err
 	}
 
-	f, err := createOutFile(adr.conf.OutputPath, "out.asciidoc")
+	if adr.conf.Render.Antora != nil {
+		return adr.renderAntora(tmpl, gvd)
+	}
+
+	f, err := adr.createOutFile(adr.conf.OutputPath, "out.asciidoc")
+	if err != nil {
+		return err
+	}
 	defer f.Close()
 
 	return tmpl.ExecuteTemplate(f, mainTemplate, gvd)
 }
 
+// renderAntora writes an index page and one page per group version into the pages directory
+// of an Antora module, along with a navigation file listing them.
+func (adr *AsciidoctorRenderer) renderAntora(tmpl *template.Template, gvd []types.GroupVersionDetails) error {
+	module := adr.conf.Render.Antora.Module
+	if module == "" {
+		module = antoraDefaultModule
+	}
+	moduleDir := filepath.Join(adr.conf.OutputPath, "modules", module)
+	pagesDir := filepath.Join(moduleDir, "pages")
+
+	adr.pages = make(map[string]string)
+	for _, gv := range gvd {
+		page := adr.antoraPage(gv)
+		adr.pages[adr.GroupVersionID(gv)] = page
+		for _, t := range gv.Types {
+			adr.pages[adr.TypeID(t)] = page
+		}
+	}
+
+	if err := adr.executeToFile(tmpl, antoraIndexTemplate, gvd, pagesDir, antoraIndexPage); err != nil {
+		return err
+	}
+
+	for _, gv := range gvd {
+		if err := adr.executeToFile(tmpl, antoraPageTemplate, gv, pagesDir, adr.antoraPage(gv)); err != nil {
+			return err
+		}
+	}
+
+	return adr.executeToFile(tmpl, antoraNavTemplate, gvd, moduleDir, antoraNavFile)
+}
+
+func (adr *AsciidoctorRenderer) antoraPage(gv types.GroupVersionDetails) string {
+	return adr.GroupVersionID(gv) + ".adoc"
+}
+
 func (adr *AsciidoctorRenderer) ToFuncMap() template.FuncMap {
 	return template.FuncMap{
+		"AnchorPrefix":       adr.AnchorPrefix,
+		"Example":            adr.Example,
+		"DOT":                adr.DOT,
+		"Examples":           adr.Examples,
 		"GroupVersionID":     adr.GroupVersionID,
+		"Mermaid":            adr.Mermaid,
 		"RenderAnchorID":     adr.RenderAnchorID,
 		"RenderExternalLink": adr.RenderExternalLink,
 		"RenderGVLink":       adr.RenderGVLink,
@@ -120,13 +180,16 @@ func (adr *AsciidoctorRenderer) RenderTypeLink(t *types.Type) string {
 	}
 
 	if local {
-		return adr.RenderLocalLink(asciidocAnchorPrefix, link, text)
+		return adr.RenderLocalLink(adr.anchorRef(), link, text)
 	} else {
 		return adr.RenderExternalLink(link, text)
 	}
 }
 
 func (adr *AsciidoctorRenderer) RenderLocalLink(prefix, link, text string) string {
+	if page, ok := adr.pages[link]; ok {
+		return fmt.Sprintf("xref:%s#%s%s[$$%s$$]", page, prefix, link, text)
+	}
 	return fmt.Sprintf("xref:%s%s[$$%s$$]", prefix, link, text)
 }
 
@@ -135,11 +198,29 @@ func (adr *AsciidoctorRenderer) RenderExternalLink(link, text string) string {
 }
 
 func (adr *AsciidoctorRenderer) RenderGVLink(gv types.GroupVersionDetails) string {
-	return adr.RenderLocalLink(asciidocAnchorPrefix, adr.GroupVersionID(gv), gv.GroupVersionString())
+	return adr.RenderLocalLink(adr.anchorRef(), adr.GroupVersionID(gv), gv.GroupVersionString())
 }
 
 func (adr *AsciidoctorRenderer) RenderAnchorID(id string) string {
-	return fmt.Sprintf("%s%s", asciidocAnchorPrefix, adr.SafeID(id))
+	return fmt.Sprintf("%s%s", adr.anchorRef(), adr.SafeID(id))
+}
+
+// AnchorPrefix returns the configured prefix of generated anchor IDs.
+func (adr *AsciidoctorRenderer) AnchorPrefix() string {
+	if adr.conf.Render.AnchorPrefix != "" {
+		return adr.conf.Render.AnchorPrefix
+	}
+	return asciidocDefaultAnchorPrefix
+}
+
+// anchorRef returns the prefix to prepend to anchor IDs. A single document refers to the
+// anchor_prefix attribute it defines, while Antora pages spell out the prefix so that
+// cross-page references do not depend on attributes defined by another page.
+func (adr *AsciidoctorRenderer) anchorRef() string {
+	if adr.conf.Render.Antora != nil {
+		return adr.AnchorPrefix() + "-"
+	}
+	return asciidocAnchorPrefix
 }
 
 func (adr *AsciidoctorRenderer) RenderFieldDoc(text string) string {
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestAsciidoctorRendererAntora(t *testing.T) {
+	outDir := t.TempDir()
+	conf := &config.Config{
+		Render: config.RenderConfig{
+			AnchorPrefix: "test",
+			Antora:       &config.AntoraConfig{Module: "api"},
+		},
+		Flags: config.Flags{OutputPath: outDir},
+	}
+
+	spec := &types.Type{Name: "Spec", Package: "example.com/api/v1", Kind: types.StructKind}
+	gvd := []types.GroupVersionDetails{
+		{
+			GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+			Types:        types.TypeMap{"Spec": spec},
+		},
+	}
+
+	adr, err := NewAsciidoctorRenderer(conf)
+	require.NoError(t, err)
+	require.NoError(t, adr.Render(gvd))
+
+	for _, f := range []string{"nav.adoc", "pages/index.adoc", "pages/example-com-v1.adoc"} {
+		require.FileExists(t, filepath.Join(outDir, "modules", "api", f))
+	}
+
+	nav, err := os.ReadFile(filepath.Join(outDir, "modules", "api", "nav.adoc"))
+	require.NoError(t, err)
+	require.Contains(t, string(nav), "** xref:example-com-v1.adoc#test-example-com-v1[$$example.com/v1$$]")
+
+	require.Equal(t, "xref:example-com-v1.adoc#test-example-com-api-v1-spec[$$Spec$$]", adr.RenderTypeLink(spec))
+	require.Equal(t, "test-example-com-api-v1-spec", adr.RenderAnchorID(adr.TypeID(spec)))
+}
@@ -0,0 +1,117 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Check renders the given group versions in memory and compares the result with the files on the
+// disk, without modifying them. It returns the unified diff between the files on the disk and the
+// rendered ones, which is empty when the files are up to date. When outputPath is a directory, the
+// files under it that have the extension of a rendered file but would not be rendered are stale,
+// and are reported as removed.
+func Check(r Renderer, gvd []types.GroupVersionDetails, outputPath string) (string, error) {
+	out := NewMemoryOutput()
+	if err := RenderTo(r, gvd, out); err != nil {
+		return "", err
+	}
+
+	stale, err := staleFiles(outputPath, out)
+	if err != nil {
+		return "", err
+	}
+
+	paths := append(out.Paths(), stale...)
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, path := range paths {
+		rendered := out.Files[path]
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if bytes.Equal(current, rendered) {
+			continue
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(current),
+			B:        splitLines(rendered),
+			FromFile: path,
+			ToFile:   path,
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(diff)
+	}
+
+	return sb.String(), nil
+}
+
+// staleFiles returns the files under the output directory that have the extension of one of the
+// rendered files but were not rendered, skipping hidden files and directories.
+func staleFiles(outputPath string, out *MemoryOutput) ([]string, error) {
+	if ok, err := out.IsDir(outputPath); err != nil || !ok {
+		return nil, err
+	}
+
+	rendered := make(map[string]bool, len(out.Files))
+	extensions := make(map[string]bool)
+	for path := range out.Files {
+		rendered[filepath.Clean(path)] = true
+		extensions[filepath.Ext(path)] = true
+	}
+
+	var stale []string
+	err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != outputPath && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && extensions[filepath.Ext(path)] && !rendered[filepath.Clean(path)] {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// splitLines splits b into lines, keeping their line endings.
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
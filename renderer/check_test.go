@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCheck(t *testing.T) {
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "size", Type: &types.Type{Name: "string", Kind: types.BasicKind}}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing},
+	}}
+
+	dir := t.TempDir()
+	conf := &config.Config{Flags: config.Flags{OutputPath: dir, MaxDepth: 6}}
+	path := filepath.Join(dir, "example.com", "thing_v1.mmd")
+
+	r, err := NewMermaidRenderer(conf)
+	require.NoError(t, err)
+	diff, err := Check(r, gvd, dir)
+	require.NoError(t, err)
+	require.Equal(t, "--- "+path+"\n+++ "+path+"\n@@ -0,0 +1,4 @@\n+classDiagram\n+  class Thing {\n+    +string size\n+  }\n", diff)
+	require.NoFileExists(t, path)
+
+	r, err = NewMermaidRenderer(conf)
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+	diff, err = Check(r, gvd, dir)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	require.NoError(t, os.WriteFile(path, []byte("classDiagram\n  class Thing\n"), 0o644))
+	diff, err = Check(r, gvd, dir)
+	require.NoError(t, err)
+	require.Contains(t, diff, "-  class Thing\n+  class Thing {\n")
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "classDiagram\n  class Thing\n", string(b))
+
+	// files of Kinds that are not rendered anymore are stale, unlike files of other types
+	r, err = NewMermaidRenderer(conf)
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+	stale := filepath.Join(dir, "example.com", "other_v1.mmd")
+	require.NoError(t, os.WriteFile(stale, []byte("classDiagram\n  class Other\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Diagrams\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "old.mmd"), []byte("classDiagram\n"), 0o644))
+	diff, err = Check(r, gvd, dir)
+	require.NoError(t, err)
+	require.Equal(t, "--- "+stale+"\n+++ "+stale+"\n@@ -1,2 +0,0 @@\n-classDiagram\n-  class Other\n", diff)
+}
@@ -0,0 +1,346 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/templates"
+	"github.com/elastic/crd-ref-docs/types"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+const cueFileName = "types_gen.cue"
+
+var (
+	cueIdentifierRegex = regexp.MustCompile(`^[A-Za-z$][A-Za-z0-9_$]*$`)
+	cuePackageRegex    = regexp.MustCompile(`[^A-Za-z0-9_]`)
+	cueKeywords        = map[string]bool{"package": true, "import": true, "for": true, "in": true, "if": true, "let": true, "true": true, "false": true, "null": true}
+)
+
+// cueWellKnownTypes holds the CUE types of types whose Go representation differs from their
+// serialized form.
+var cueWellKnownTypes = map[string]string{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                          "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                     "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                      "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                    "{name?: string, namespace?: string, labels?: {[string]: string}, annotations?: {[string]: string}, ...}",
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                      "int | string",
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                    "int | string",
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                       "{...}",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON":      "_",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1.JSON": "_",
+}
+
+// CueRenderer writes the CUE definitions of every group version into
+// <group>/<version>/types_gen.cue in the output directory, as a package named after the version.
+type CueRenderer struct {
+	conf *config.Config
+	// names holds the declared names of the types of the group version being rendered, by key.
+	names map[string]string
+	fileWriter
+}
+
+func NewCueRenderer(conf *config.Config) (*CueRenderer, error) {
+	return &CueRenderer{conf: conf}, nil
+}
+
+func (c *CueRenderer) Render(gvd []types.GroupVersionDetails) error {
+	funcMap := combinedFuncMap(funcMap{prefix: "cue", funcs: c.ToFuncMap()}, funcMap{funcs: sprig.TxtFuncMap()})
+
+	var tpls fs.FS
+	if c.conf.TemplatesDir != "" {
+		tpls = os.DirFS(c.conf.TemplatesDir)
+	} else {
+		sub, err := fs.Sub(templates.Root, "cue")
+		if err != nil {
+			return err
+		}
+		tpls = sub
+	}
+
+	tmpl, err := loadTemplate(tpls, funcMap)
+	if err != nil {
+		return err
+	}
+
+	for _, gv := range gvd {
+		c.names = declaredNames(c.Declarations(gv))
+		if err := c.executeToFile(tmpl, "gvDetails", gv, filepath.Join(c.conf.OutputPath, gv.Group, gv.Version), cueFileName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *CueRenderer) ToFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"Comment":      c.Comment,
+		"Declarations": c.Declarations,
+		"FieldName":    c.FieldName,
+		"Fields":       c.Fields,
+		"Imports":      c.Imports,
+		"IsStruct":     c.IsStruct,
+		"Name":         c.Name,
+		"PackageName":  c.PackageName,
+		"Quote":        strconv.Quote,
+		"RenderAlias":  c.RenderAlias,
+		"RenderField":  c.RenderField,
+		"RenderType":   c.RenderType,
+	}
+}
+
+// PackageName returns the name of the CUE package of a group version.
+func (c *CueRenderer) PackageName(gv types.GroupVersionDetails) string {
+	name := cuePackageRegex.ReplaceAllString(gv.Version, "_")
+	if name == "" || !cueIdentifierRegex.MatchString(name) {
+		name = "v" + name
+	}
+	return name
+}
+
+// Declarations returns the named types to declare for the given group version: its own types and
+// the types they refer to from other packages, sorted by name.
+func (c *CueRenderer) Declarations(gv types.GroupVersionDetails) []*types.Type {
+	return declaredTypes(gv, cueWellKnownTypes)
+}
+
+// Name returns the name of the definition of the given type, without its leading #, qualified by
+// its package when several declared types share its name.
+func (c *CueRenderer) Name(t *types.Type) string {
+	if name, ok := c.names[types.Key(t)]; ok {
+		return name
+	}
+	return t.Name
+}
+
+// Imports returns the packages of the standard library used by the constraints of the declarations.
+func (c *CueRenderer) Imports(gv types.GroupVersionDetails) []string {
+	for _, t := range c.Declarations(gv) {
+		if cueUsesStrings(t.Markers, t.Members()) {
+			return []string{"strings"}
+		}
+	}
+	return nil
+}
+
+// cueUsesStrings returns true if the given markers, or those of the given fields and of the fields
+// of the anonymous structs they hold, are rendered as constraints of the strings package.
+func cueUsesStrings(values markers.MarkerValues, fields types.Fields) bool {
+	if values.Get(types.MaxLengthMarker) != nil || values.Get(types.MinLengthMarker) != nil {
+		return true
+	}
+
+	for _, f := range fields {
+		if cueUsesStrings(f.Markers, nil) {
+			return true
+		}
+
+		// anonymous structs are rendered inline, within the declaration holding them
+		t := f.Type
+		for t != nil && t.Name == "" && t.Kind != types.StructKind {
+			if t.Kind == types.MapKind {
+				t = t.ValueType
+			} else {
+				t = t.UnderlyingType
+			}
+		}
+		if t != nil && t.Name == "" && cueUsesStrings(nil, t.Members()) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStruct returns true if the given type is declared as a struct rather than as a constrained value.
+func (c *CueRenderer) IsStruct(t *types.Type) bool {
+	return t.Kind == types.StructKind
+}
+
+// Fields returns the fields of the given type, except for the apiVersion and kind of Kinds which
+// are declared separately.
+func (c *CueRenderer) Fields(t *types.Type) types.Fields {
+	var fields types.Fields
+	for _, f := range t.Members() {
+		if f.Name == "" || (t.GVK != nil && (f.Name == "apiVersion" || f.Name == "kind")) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// FieldName returns the label of a field, including the question mark of optional fields.
+func (c *CueRenderer) FieldName(f *types.Field) string {
+	name := f.Name
+	if !cueIdentifierRegex.MatchString(name) || cueKeywords[name] {
+		name = strconv.Quote(name)
+	}
+	if f.Optional {
+		name += "?"
+	}
+	return name
+}
+
+// RenderType returns the CUE type of a value of the given type.
+func (c *CueRenderer) RenderType(t *types.Type) string {
+	if t == nil {
+		return "_"
+	}
+
+	if cueType, ok := cueWellKnownTypes[types.Key(t)]; ok {
+		return cueType
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		return c.RenderType(t.UnderlyingType)
+
+	case types.SliceKind, types.ArrayKind:
+		if e := t.UnderlyingType; e != nil && e.Kind == types.BasicKind && (e.Name == "byte" || e.Name == "uint8") {
+			// byte slices are serialized as base64 strings
+			return "string"
+		}
+		return "[..." + c.RenderType(t.UnderlyingType) + "]"
+
+	case types.MapKind:
+		return "{[string]: " + c.RenderType(t.ValueType) + "}"
+
+	case types.BasicKind:
+		switch t.Name {
+		case "string", "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "rune", "float32", "float64":
+			return t.Name
+		case "byte":
+			return "uint8"
+		default:
+			return "_"
+		}
+
+	case types.StructKind:
+		if t.Name != "" {
+			return "#" + c.Name(t)
+		}
+
+		fields := c.Fields(t)
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = c.FieldName(f) + ": " + c.RenderField(f)
+		}
+		return "{" + strings.Join(values, ", ") + "}"
+
+	case types.AliasKind:
+		return "#" + c.Name(t)
+
+	default:
+		return "_"
+	}
+}
+
+// RenderAlias returns the value that a named alias type is declared as: its allowed values or the
+// type it is an alias of, constrained by its validation markers.
+func (c *CueRenderer) RenderAlias(t *types.Type) string {
+	return c.constrain(c.RenderType(t.UnderlyingType), t.Markers, nil)
+}
+
+// RenderField returns the value of a field: its type constrained by the validation markers of the
+// field, with its default value, if any, marked as such.
+func (c *CueRenderer) RenderField(f *types.Field) string {
+	return c.constrain(c.RenderType(f.Type), f.Markers, f.Default)
+}
+
+func (c *CueRenderer) constrain(expr string, values markers.MarkerValues, def any) string {
+	if enum, ok := values.Get(types.EnumMarker).(crdmarkers.Enum); ok && len(enum) > 0 {
+		literals := make([]string, len(enum))
+		for i, v := range enum {
+			literals[i] = jsonString(v)
+		}
+		expr = strings.Join(literals, " | ")
+	}
+
+	if constraints := cueConstraints(values); len(constraints) > 0 {
+		if strings.Contains(expr, " | ") {
+			expr = "(" + expr + ")"
+		}
+		expr = strings.Join(append([]string{expr}, constraints...), " & ")
+	}
+
+	if def != nil {
+		expr = "*" + jsonString(def) + " | " + expr
+	}
+
+	return expr
+}
+
+func cueConstraints(values markers.MarkerValues) []string {
+	var constraints []string
+
+	if v, ok := values.Get(types.MinimumMarker).(crdmarkers.Minimum); ok {
+		op := ">="
+		if exclusive, _ := values.Get(types.ExclusiveMinimumMarker).(crdmarkers.ExclusiveMinimum); bool(exclusive) {
+			op = ">"
+		}
+		constraints = append(constraints, op+strconv.FormatFloat(float64(v), 'g', -1, 64))
+	}
+	if v, ok := values.Get(types.MaximumMarker).(crdmarkers.Maximum); ok {
+		op := "<="
+		if exclusive, _ := values.Get(types.ExclusiveMaximumMarker).(crdmarkers.ExclusiveMaximum); bool(exclusive) {
+			op = "<"
+		}
+		constraints = append(constraints, op+strconv.FormatFloat(float64(v), 'g', -1, 64))
+	}
+	if v, ok := values.Get(types.MinLengthMarker).(crdmarkers.MinLength); ok {
+		constraints = append(constraints, fmt.Sprintf("strings.MinRunes(%d)", v))
+	}
+	if v, ok := values.Get(types.MaxLengthMarker).(crdmarkers.MaxLength); ok {
+		constraints = append(constraints, fmt.Sprintf("strings.MaxRunes(%d)", v))
+	}
+	if v, ok := values.Get(types.PatternMarker).(crdmarkers.Pattern); ok {
+		constraints = append(constraints, "=~"+strconv.Quote(string(v)))
+	}
+
+	return constraints
+}
+
+// Comment returns the documentation as line comments prefixed by indent, or an empty string if
+// there is no documentation.
+func (c *CueRenderer) Comment(doc string, indent string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, l := range strings.Split(doc, "\n") {
+		if l = strings.TrimSpace(l); l == "" {
+			sb.WriteString(indent + "//\n")
+		} else {
+			sb.WriteString(indent + "// " + l + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestCueRenderer(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	level := &types.Type{
+		Name:           "Level",
+		Package:        "example.com/api/v1",
+		Doc:            "Level of the thing.",
+		Kind:           types.AliasKind,
+		UnderlyingType: str,
+		Markers:        markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum{"low", "high"}}},
+	}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "level", Type: level, Doc: "Level to use.", Default: "low", Optional: true},
+		{Name: "replicas", Type: &types.Type{Name: "int32", Kind: types.BasicKind}, Markers: markers.MarkerValues{
+			types.MinimumMarker:          {crdmarkers.Minimum(0)},
+			types.ExclusiveMinimumMarker: {crdmarkers.ExclusiveMinimum(true)},
+		}},
+		{Name: "name", Type: str, Optional: true, Markers: markers.MarkerValues{types.MaxLengthMarker: {crdmarkers.MaxLength(10)}}},
+		{Name: "labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}, Optional: true},
+		{Name: "x-items", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: str}},
+	}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "spec", Type: spec}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec},
+	}}
+
+	dir := t.TempDir()
+	r, err := NewCueRenderer(&config.Config{Flags: config.Flags{OutputPath: dir}})
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+
+	out, err := os.ReadFile(filepath.Join(dir, "example.com", "v1", "types_gen.cue"))
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by crd-ref-docs. DO NOT EDIT.
+
+package v1
+
+import (
+	"strings"
+)
+
+// Level of the thing.
+#Level: "low" | "high"
+
+#Thing: {
+	apiVersion: "example.com/v1"
+	kind:       "Thing"
+	spec: #ThingSpec
+}
+
+#ThingSpec: {
+	// Level to use.
+	level?: *"low" | #Level
+	replicas: int32 & >0
+	name?: string & strings.MaxRunes(10)
+	labels?: {[string]: string}
+	"x-items": [...string]
+}
+`, string(out))
+}
+
+func TestCueRendererCollidingNames(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	v1Status := &types.Type{Name: "Status", Package: "example.com/api/v1", Kind: types.StructKind, Fields: types.Fields{{Name: "phase", Type: str}}}
+	otherStatus := &types.Type{Name: "Status", Package: "example.com/other/v1", Kind: types.StructKind, Fields: types.Fields{{Name: "ready", Type: str}}}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Fields:  types.Fields{{Name: "status", Type: v1Status}, {Name: "otherStatus", Type: otherStatus}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Types:        types.TypeMap{"Thing": thing, "Status": v1Status},
+	}}
+
+	dir := t.TempDir()
+	r, err := NewCueRenderer(&config.Config{Flags: config.Flags{OutputPath: dir}})
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+
+	out, err := os.ReadFile(filepath.Join(dir, "example.com", "v1", "types_gen.cue"))
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by crd-ref-docs. DO NOT EDIT.
+
+package v1
+
+#ApiV1Status: {
+	phase: string
+}
+
+#OtherV1Status: {
+	ready: string
+}
+
+#Thing: {
+	status: #ApiV1Status
+	otherStatus: #OtherV1Status
+}
+`, string(out))
+}
+
+func TestCueRendererImports(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	selector := &types.Type{Kind: types.StructKind, Fields: types.Fields{{Name: "name", Type: str}}}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Fields: types.Fields{
+			{Name: "selectors", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Kind: types.PointerKind, UnderlyingType: selector}}},
+		},
+	}
+	gv := types.GroupVersionDetails{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Types:        types.TypeMap{"Thing": thing},
+	}
+
+	r, err := NewCueRenderer(&config.Config{})
+	require.NoError(t, err)
+	require.Empty(t, r.Imports(gv))
+
+	// the constraints of the fields of anonymous structs are rendered within the declaration
+	selector.Fields[0].Markers = markers.MarkerValues{types.MinLengthMarker: {crdmarkers.MinLength(1)}}
+	require.Equal(t, []string{"strings"}, r.Imports(gv))
+}
@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/diagram"
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// DiagramRenderer writes a diagram of the types making up every Kind, laid out as
+// <group>/<kind>_<version>.<extension> in the output directory.
+type DiagramRenderer struct {
+	conf *config.Config
+	fileWriter
+	extension string
+	draw      func(g *diagram.Graph, name string) string
+}
+
+// NewMermaidRenderer returns a renderer writing Mermaid class diagrams.
+func NewMermaidRenderer(conf *config.Config) (*DiagramRenderer, error) {
+	return &DiagramRenderer{
+		conf:      conf,
+		extension: ".mmd",
+		draw: func(g *diagram.Graph, _ string) string {
+			return g.Mermaid()
+		},
+	}, nil
+}
+
+// NewDOTRenderer returns a renderer writing Graphviz graphs.
+func NewDOTRenderer(conf *config.Config) (*DiagramRenderer, error) {
+	return &DiagramRenderer{conf: conf, extension: ".dot", draw: (*diagram.Graph).DOT}, nil
+}
+
+func (d *DiagramRenderer) Render(gvd []types.GroupVersionDetails) error {
+	for _, gv := range gvd {
+		dir := filepath.Join(d.conf.OutputPath, gv.Group)
+		for _, kind := range gv.SortedKinds() {
+			t := gv.TypeForKind(kind)
+			if t == nil {
+				continue
+			}
+
+			g := diagram.New(t, d.conf.MaxDepth)
+			fileName := strings.ToLower(kind) + "_" + gv.Version + d.extension
+			if err := d.writeFile(dir, fileName, []byte(d.draw(g, kind))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDiagramRenderers(t *testing.T) {
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "name", Type: &types.Type{Name: "string", Kind: types.BasicKind}}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing},
+	}}
+
+	dir := t.TempDir()
+	conf := &config.Config{Flags: config.Flags{OutputPath: dir, MaxDepth: 6}}
+
+	mermaid, err := NewMermaidRenderer(conf)
+	require.NoError(t, err)
+	require.NoError(t, mermaid.Render(gvd))
+	out, err := os.ReadFile(filepath.Join(dir, "example.com", "thing_v1.mmd"))
+	require.NoError(t, err)
+	require.Equal(t, "classDiagram\n  class Thing {\n    +string name\n  }\n", string(out))
+
+	dot, err := NewDOTRenderer(conf)
+	require.NoError(t, err)
+	require.NoError(t, dot.Render(gvd))
+	out, err = os.ReadFile(filepath.Join(dir, "example.com", "thing_v1.dot"))
+	require.NoError(t, err)
+	require.Contains(t, string(out), `Thing [label="{Thing|name: string\l}"];`)
+}
+
+func TestDiagramFunctions(t *testing.T) {
+	conf := &config.Config{Flags: config.Flags{MaxDepth: 6}}
+	markdown, err := NewMarkdownRenderer(conf)
+	require.NoError(t, err)
+	asciidoctor, err := NewAsciidoctorRenderer(conf)
+	require.NoError(t, err)
+	html, err := NewHTMLRenderer(conf)
+	require.NoError(t, err)
+	rst, err := NewRstRenderer(conf)
+	require.NoError(t, err)
+
+	for name, funcs := range map[string]map[string]any{
+		"markdown":    markdown.ToFuncMap(),
+		"asciidoctor": asciidoctor.ToFuncMap(),
+		"html":        html.ToFuncMap(),
+		"rst":         rst.ToFuncMap(),
+	} {
+		require.Contains(t, funcs, "Mermaid", name)
+		require.Contains(t, funcs, "DOT", name)
+	}
+}
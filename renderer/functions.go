@@ -24,6 +24,8 @@ import (
 	"text/template"
 
 	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/diagram"
+	"github.com/elastic/crd-ref-docs/example"
 	"github.com/elastic/crd-ref-docs/types"
 	"go.uber.org/zap"
 )
@@ -69,6 +71,35 @@ func (f *Functions) SafeID(id string) string {
 	return strings.ToLower(f.safeIDRegex.ReplaceAllLiteralString(id, "-"))
 }
 
+// Example returns an example manifest of the Kind described by the given root type.
+func (f *Functions) Example(t *types.Type) string {
+	return example.Generate(t, f.conf.MaxDepth)
+}
+
+// Mermaid returns a Mermaid class diagram of the types making up the given type.
+func (f *Functions) Mermaid(t *types.Type) string {
+	return diagram.New(t, f.conf.MaxDepth).Mermaid()
+}
+
+// DOT returns a Graphviz graph of the types making up the given type.
+func (f *Functions) DOT(t *types.Type) string {
+	return diagram.New(t, f.conf.MaxDepth).DOT(t.Name)
+}
+
+// Examples returns the sample manifests attached to the given root type or, when there are none,
+// a single generated example.
+func (f *Functions) Examples(t *types.Type) []types.Example {
+	if len(t.Examples) == 0 {
+		return []types.Example{{Content: f.Example(t)}}
+	}
+
+	examples := make([]types.Example, len(t.Examples))
+	for i, e := range t.Examples {
+		examples[i] = types.Example{Source: e.Source, Content: strings.TrimRight(e.Content, "\n") + "\n"}
+	}
+	return examples
+}
+
 func (f *Functions) LinkForType(t *types.Type) (link string, local bool) {
 	if f.IsKubeType(t) {
 		return f.LinkForKubeType(t), false
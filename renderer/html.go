@@ -0,0 +1,251 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/sprig"
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/templates"
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+const htmlMaxSearchDocLength = 160
+
+type HTMLRenderer struct {
+	conf *config.Config
+	fileWriter
+	*Functions
+}
+
+func NewHTMLRenderer(conf *config.Config) (*HTMLRenderer, error) {
+	baseFuncs, err := NewFunctions(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &HTMLRenderer{conf: conf, Functions: baseFuncs}, nil
+}
+
+func (h *HTMLRenderer) Render(gvd []types.GroupVersionDetails) error {
+	funcMap := combinedFuncMap(funcMap{prefix: "html", funcs: h.ToFuncMap()}, funcMap{funcs: sprig.TxtFuncMap()})
+
+	var tpls fs.FS
+	if h.conf.TemplatesDir != "" {
+		tpls = os.DirFS(h.conf.TemplatesDir)
+	} else {
+		sub, err := fs.Sub(templates.Root, "html")
+		if err != nil {
+			return err
+		}
+		tpls = sub
+	}
+
+	tmpl, err := template.New("").Funcs(template.FuncMap(funcMap)).ParseFS(tpls, "*.tpl")
+	if err != nil {
+		return err
+	}
+
+	f, err := h.createOutFile(h.conf.OutputPath, "index.html")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.ExecuteTemplate(f, mainTemplate, gvd)
+}
+
+func (h *HTMLRenderer) ToFuncMap() map[string]any {
+	return map[string]any{
+		"DOT":                h.DOT,
+		"Example":            h.Example,
+		"Examples":           h.Examples,
+		"FieldID":            h.FieldID,
+		"FieldTree":          h.FieldTree,
+		"GroupVersionID":     h.GroupVersionID,
+		"Mermaid":            h.Mermaid,
+		"RenderExternalLink": h.RenderExternalLink,
+		"RenderGVLink":       h.RenderGVLink,
+		"RenderLocalLink":    h.RenderLocalLink,
+		"RenderType":         h.RenderType,
+		"RenderTypeLink":     h.RenderTypeLink,
+		"SafeID":             h.SafeID,
+		"SearchIndex":        h.SearchIndex,
+		"ShouldRenderType":   h.ShouldRenderType,
+		"TypeID":             h.TypeID,
+	}
+}
+
+func (h *HTMLRenderer) ShouldRenderType(t *types.Type) bool {
+	return t != nil && (t.GVK != nil || len(t.References) > 0)
+}
+
+func (h *HTMLRenderer) RenderType(t *types.Type) template.HTML {
+	var sb strings.Builder
+	switch t.Kind {
+	case types.MapKind:
+		sb.WriteString("object (keys:")
+		sb.WriteString(string(h.RenderTypeLink(t.KeyType)))
+		sb.WriteString(", values:")
+		sb.WriteString(string(h.RenderTypeLink(t.ValueType)))
+		sb.WriteString(")")
+	case types.ArrayKind, types.SliceKind:
+		sb.WriteString(string(h.RenderTypeLink(t.UnderlyingType)))
+		sb.WriteString(" array")
+	default:
+		sb.WriteString(string(h.RenderTypeLink(t)))
+	}
+
+	return template.HTML(sb.String())
+}
+
+func (h *HTMLRenderer) RenderTypeLink(t *types.Type) template.HTML {
+	text := h.SimplifiedTypeName(t)
+
+	link, local := h.LinkForType(t)
+	if link == "" {
+		return template.HTML(template.HTMLEscapeString(text))
+	}
+
+	if local {
+		return h.RenderLocalLink(link, text)
+	} else {
+		return h.RenderExternalLink(link, text)
+	}
+}
+
+func (h *HTMLRenderer) RenderLocalLink(id, text string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<a href="#%s">%s</a>`, template.HTMLEscapeString(id), template.HTMLEscapeString(text)))
+}
+
+func (h *HTMLRenderer) RenderExternalLink(link, text string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<a href="%s" class="external">%s</a>`, template.HTMLEscapeString(link), template.HTMLEscapeString(text)))
+}
+
+func (h *HTMLRenderer) RenderGVLink(gv types.GroupVersionDetails) template.HTML {
+	return h.RenderLocalLink(h.GroupVersionID(gv), gv.GroupVersionString())
+}
+
+// FieldID returns the anchor ID of a field, identified by its path relative to the given type.
+func (h *HTMLRenderer) FieldID(t *types.Type, path string) string {
+	return h.TypeID(t) + "--" + path
+}
+
+// FieldNode is an entry of the nested field tree of a type.
+type FieldNode struct {
+	*types.Field
+	ID       string
+	Path     string
+	Children []*FieldNode
+}
+
+// FieldTree returns the members of the given type with the members of their own types nested
+// below them, so that the whole structure of a type can be browsed from a single place.
+// Recursion stops at types already present on the current path and at the configured maximum depth.
+func (h *HTMLRenderer) FieldTree(t *types.Type) []*FieldNode {
+	return h.fieldTree(t, t, "", map[string]bool{types.Key(t): true}, 0)
+}
+
+func (h *HTMLRenderer) fieldTree(root, t *types.Type, prefix string, seen map[string]bool, depth int) []*FieldNode {
+	var nodes []*FieldNode
+	for _, f := range t.Members() {
+		node := &FieldNode{Field: f, Path: prefix + f.Name}
+		node.ID = h.FieldID(root, node.Path)
+
+		child := f.Type.ElemType()
+		if child != nil && !child.IsBasic() && !h.IsKubeType(child) && depth < h.conf.MaxDepth {
+			key := types.Key(child)
+			if !seen[key] {
+				seen[key] = true
+				node.Children = h.fieldTree(root, child, node.Path+".", seen, depth+1)
+				delete(seen, key)
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// SearchEntry is an element of the client-side search index.
+type SearchEntry struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Group  string `json:"group"`
+	Anchor string `json:"anchor"`
+	Doc    string `json:"doc,omitempty"`
+}
+
+// SearchIndex returns the entries that the generated page searches through: group versions,
+// rendered types and their fields.
+func (h *HTMLRenderer) SearchIndex(gvd []types.GroupVersionDetails) []SearchEntry {
+	var entries []SearchEntry
+	for _, gv := range gvd {
+		gvName := gv.GroupVersionString()
+		entries = append(entries, SearchEntry{
+			Name:   gvName,
+			Kind:   "package",
+			Group:  gvName,
+			Anchor: h.GroupVersionID(gv),
+			Doc:    searchDoc(gv.Doc),
+		})
+
+		for _, t := range gv.SortedTypes() {
+			if !h.ShouldRenderType(t) {
+				continue
+			}
+
+			kind := "type"
+			if t.GVK != nil {
+				kind = "kind"
+			}
+			entries = append(entries, SearchEntry{
+				Name:   t.Name,
+				Kind:   kind,
+				Group:  gvName,
+				Anchor: h.TypeID(t),
+				Doc:    searchDoc(t.Doc),
+			})
+
+			for _, f := range t.Members() {
+				entries = append(entries, SearchEntry{
+					Name:   t.Name + "." + f.Name,
+					Kind:   "field",
+					Group:  gvName,
+					Anchor: h.FieldID(t, f.Name),
+					Doc:    searchDoc(f.Doc),
+				})
+			}
+		}
+	}
+
+	return entries
+}
+
+// searchDoc shortens documentation to a single line suitable for search results.
+func searchDoc(doc string) string {
+	runes := []rune(strings.Join(strings.Fields(doc), " "))
+	if len(runes) > htmlMaxSearchDocLength {
+		return strings.TrimSpace(string(runes[:htmlMaxSearchDocLength])) + "…"
+	}
+	return string(runes)
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLRendererFieldTree(t *testing.T) {
+	h, err := NewHTMLRenderer(&config.Config{Flags: config.Flags{MaxDepth: 5}})
+	require.NoError(t, err)
+
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	node := &types.Type{Name: "Node", Package: "example.com/api/v1", Kind: types.StructKind}
+	node.Fields = types.Fields{
+		{Name: "name", Type: str},
+		{Name: "children", Type: &types.Type{Name: "Node", Package: "example.com/api/v1", Kind: types.SliceKind, UnderlyingType: node}},
+	}
+
+	tree := h.FieldTree(node)
+	require.Len(t, tree, 2)
+	require.Equal(t, "example-com-api-v1-node--name", tree[0].ID)
+	require.Equal(t, "children", tree[1].Path)
+	// recursive types are not expanded again
+	require.Empty(t, tree[1].Children)
+}
+
+func TestHTMLRendererEscaping(t *testing.T) {
+	h, err := NewHTMLRenderer(&config.Config{})
+	require.NoError(t, err)
+
+	require.Equal(t, `<a href="#a&lt;b">x &amp; &lt;y&gt;</a>`, string(h.RenderLocalLink("a<b", "x & <y>")))
+	require.Equal(t, "&lt;T&gt;", string(h.RenderTypeLink(&types.Type{Name: "<T>", Kind: types.BasicKind})))
+}
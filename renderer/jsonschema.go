@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/jsonschema"
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// JSONSchemaRenderer writes a JSON Schema file per Kind, laid out as <group>/<kind>_<version>.json
+// in the output directory so that YAML language servers can look them up.
+type JSONSchemaRenderer struct {
+	conf *config.Config
+	fileWriter
+}
+
+func NewJSONSchemaRenderer(conf *config.Config) (*JSONSchemaRenderer, error) {
+	return &JSONSchemaRenderer{conf: conf}, nil
+}
+
+func (j *JSONSchemaRenderer) Render(gvd []types.GroupVersionDetails) error {
+	for _, gv := range gvd {
+		for _, kind := range gv.SortedKinds() {
+			t := gv.TypeForKind(kind)
+			if t == nil || t.GVK == nil {
+				continue
+			}
+
+			fileName := strings.ToLower(kind) + "_" + gv.Version + ".json"
+			if err := j.writeJSON(filepath.Join(j.conf.OutputPath, gv.Group), fileName, KindSchema(t)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// KindSchema returns the standalone JSON schema of the Kind described by the given root type.
+func KindSchema(t *types.Type) *jsonschema.Schema {
+	b := jsonschema.NewBuilder(jsonschema.JSONSchema, "#/$defs/")
+	s := b.Kind(t)
+	s.Schema = jsonschema.Draft202012
+	s.Title = t.GVK.Kind
+	if len(b.Defs) > 0 {
+		s.Defs = b.Defs
+	}
+	return s
+}
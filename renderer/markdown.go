@@ -31,6 +31,7 @@ import (
 
 type MarkdownRenderer struct {
 	conf *config.Config
+	fileWriter
 	*Functions
 }
 
@@ -61,7 +62,10 @@ func (m *MarkdownRenderer) Render(gvd []types.GroupVersionDetails) error {
 		return err
 	}
 
-	f, err := createOutFile(m.conf.OutputPath, "out.md")
+	f, err := m.createOutFile(m.conf.OutputPath, "out.md")
+	if err != nil {
+		return err
+	}
 	defer f.Close()
 
 	return tmpl.ExecuteTemplate(f, mainTemplate, gvd)
@@ -69,7 +73,11 @@ func (m *MarkdownRenderer) Render(gvd []types.GroupVersionDetails) error {
 
 func (m *MarkdownRenderer) ToFuncMap() template.FuncMap {
 	return template.FuncMap{
+		"Example":            m.Example,
+		"DOT":                m.DOT,
+		"Examples":           m.Examples,
 		"GroupVersionID":     m.GroupVersionID,
+		"Mermaid":            m.Mermaid,
 		"RenderExternalLink": m.RenderExternalLink,
 		"RenderGVLink":       m.RenderGVLink,
 		"RenderLocalLink":    m.RenderLocalLink,
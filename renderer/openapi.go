@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"encoding/json"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/jsonschema"
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+	// openAPITitle and openAPIInfoVersion match the info of the documents served by the Kubernetes API server.
+	openAPITitle       = "Kubernetes"
+	openAPIInfoVersion = "unversioned"
+)
+
+// OpenAPIDocument is an OpenAPI 3 document holding schemas only.
+type OpenAPIDocument struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       OpenAPIInfo       `json:"info"`
+	Paths      map[string]any    `json:"paths"`
+	Components OpenAPIComponents `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*jsonschema.Schema `json:"schemas"`
+}
+
+// OpenAPIRenderer writes an OpenAPI 3 document in which every processed type is a component schema.
+type OpenAPIRenderer struct {
+	conf *config.Config
+	fileWriter
+}
+
+func NewOpenAPIRenderer(conf *config.Config) (*OpenAPIRenderer, error) {
+	return &OpenAPIRenderer{conf: conf}, nil
+}
+
+func (o *OpenAPIRenderer) Render(gvd []types.GroupVersionDetails) error {
+	f, err := o.createOutFile(o.conf.OutputPath, "openapi.json")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewOpenAPIDocument(gvd))
+}
+
+// NewOpenAPIDocument returns the OpenAPI document describing the given group versions. Kinds are
+// annotated with x-kubernetes-group-version-kind.
+func NewOpenAPIDocument(gvd []types.GroupVersionDetails) *OpenAPIDocument {
+	b := jsonschema.NewBuilder(jsonschema.OpenAPI, "#/components/schemas/")
+	var kinds []*types.Type
+	for _, gv := range gvd {
+		for _, t := range gv.SortedTypes() {
+			switch {
+			case t.GVK != nil:
+				kinds = append(kinds, t)
+			case t.Kind == types.StructKind || t.Kind == types.AliasKind:
+				b.Define(t)
+			}
+		}
+	}
+
+	// Kinds are described last as they may also be referred to by other types
+	for _, t := range kinds {
+		b.Defs[jsonschema.DefinitionName(t)] = b.Kind(t)
+	}
+
+	return &OpenAPIDocument{
+		OpenAPI:    openAPIVersion,
+		Info:       OpenAPIInfo{Title: openAPITitle, Version: openAPIInfoVersion},
+		Paths:      map[string]any{},
+		Components: OpenAPIComponents{Schemas: b.Defs},
+	}
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/jsonschema"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	item := &types.Type{Name: "Item", Package: "example.com/api/v1", Kind: types.StructKind}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+	}
+	thingList := &types.Type{
+		Name:    "ThingList",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "ThingList"},
+		Fields:  types.Fields{{Name: "items", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: thing}}},
+	}
+	thing.Fields = types.Fields{{Name: "item", Type: item}}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing", "ThingList"},
+		Types:        types.TypeMap{"Item": item, "Thing": thing, "ThingList": thingList},
+	}}
+
+	doc := NewOpenAPIDocument(gvd)
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+
+	schemas := doc.Components.Schemas
+	require.Len(t, schemas, 3)
+	require.Contains(t, schemas, "com.example.api.v1.Item")
+	require.Equal(t, []jsonschema.GroupVersionKind{{Group: "example.com", Version: "v1", Kind: "Thing"}}, schemas["com.example.api.v1.Thing"].XKubernetesGroupVersionKind)
+	require.Equal(t, "#/components/schemas/com.example.api.v1.Thing", schemas["com.example.api.v1.ThingList"].Properties["items"].Items.Ref)
+}
@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// Output is the destination of the files written by renderers.
+type Output interface {
+	// Create creates or truncates the file at path.
+	Create(path string) (io.WriteCloser, error)
+	// MkdirAll creates the directory at path, along with its parents.
+	MkdirAll(path string) error
+	// IsDir reports whether path is an existing directory.
+	IsDir(path string) (bool, error)
+}
+
+// DiskOutput writes files to the disk.
+type DiskOutput struct{}
+
+func (DiskOutput) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+func (DiskOutput) MkdirAll(path string) error {
+	return os.MkdirAll(path, 0o755)
+}
+
+func (DiskOutput) IsDir(path string) (bool, error) {
+	finfo, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return finfo.IsDir(), nil
+}
+
+// MemoryOutput keeps the written files in memory. Directories are looked up on the disk, so that
+// files are given the same paths as when they are written to the disk.
+type MemoryOutput struct {
+	DiskOutput
+	// Files maps the paths of the written files to their content.
+	Files map[string][]byte
+}
+
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{Files: make(map[string][]byte)}
+}
+
+func (m *MemoryOutput) Create(path string) (io.WriteCloser, error) {
+	return &memoryFile{path: path, output: m}, nil
+}
+
+func (m *MemoryOutput) MkdirAll(string) error {
+	return nil
+}
+
+// Paths returns the paths of the written files, sorted.
+func (m *MemoryOutput) Paths() []string {
+	paths := make([]string, 0, len(m.Files))
+	for p := range m.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+type memoryFile struct {
+	bytes.Buffer
+	path   string
+	output *MemoryOutput
+}
+
+func (f *memoryFile) Close() error {
+	f.output.Files[f.path] = f.Bytes()
+	return nil
+}
+
+// RenderTo renders the given group versions with r, writing the files to out instead of the disk.
+func RenderTo(r Renderer, gvd []types.GroupVersionDetails, out Output) error {
+	w, ok := r.(interface{ setOutput(Output) })
+	if !ok {
+		return errors.New("renderer does not support writing to another output")
+	}
+
+	w.setOutput(out)
+	return r.Render(gvd)
+}
+
+// fileWriter is embedded in renderers to write their files to an Output, the disk unless set
+// otherwise.
+type fileWriter struct {
+	out Output
+}
+
+func (w *fileWriter) setOutput(out Output) {
+	w.out = out
+}
+
+func (w *fileWriter) output() Output {
+	if w.out == nil {
+		return DiskOutput{}
+	}
+	return w.out
+}
+
+// createOutFile creates the file at outputPath or, if outputPath is a directory, the file called
+// defaultFileName inside it.
+func (w *fileWriter) createOutFile(outputPath string, defaultFileName string) (io.WriteCloser, error) {
+	isDir, err := w.output().IsDir(outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDir {
+		outputPath = filepath.Join(outputPath, defaultFileName)
+	}
+
+	return w.output().Create(outputPath)
+}
+
+// createFile creates a file called fileName inside dir, creating the directory if it does not
+// exist yet.
+func (w *fileWriter) createFile(dir, fileName string) (io.WriteCloser, error) {
+	if err := w.output().MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return w.output().Create(filepath.Join(dir, fileName))
+}
+
+// executeToFile renders the named template into a file called fileName inside dir, creating
+// the directory if it does not exist yet.
+func (w *fileWriter) executeToFile(tmpl *template.Template, name string, data any, dir, fileName string) error {
+	f, err := w.createFile(dir, fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.ExecuteTemplate(f, name, data)
+}
+
+// writeJSON writes the indented JSON encoding of v into a file called fileName inside dir,
+// creating the directory if it does not exist yet.
+func (w *fileWriter) writeJSON(dir, fileName string, v any) error {
+	f, err := w.createFile(dir, fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeFile writes data into a file called fileName inside dir, creating the directory if it
+// does not exist yet.
+func (w *fileWriter) writeFile(dir, fileName string, data []byte) error {
+	f, err := w.createFile(dir, fileName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
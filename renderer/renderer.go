@@ -19,8 +19,9 @@ package renderer
 import (
 	"fmt"
 	"io/fs"
-	"os"
-	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/elastic/crd-ref-docs/config"
@@ -29,6 +30,8 @@ import (
 
 const mainTemplate = "gvList"
 
+var identifierSeparatorRegex = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
 type Renderer interface {
 	Render(gvd []types.GroupVersionDetails) error
 }
@@ -39,6 +42,22 @@ func New(conf *config.Config) (Renderer, error) {
 		return NewAsciidoctorRenderer(conf)
 	case "markdown":
 		return NewMarkdownRenderer(conf)
+	case "html":
+		return NewHTMLRenderer(conf)
+	case "rst":
+		return NewRstRenderer(conf)
+	case "jsonschema":
+		return NewJSONSchemaRenderer(conf)
+	case "openapi":
+		return NewOpenAPIRenderer(conf)
+	case "typescript":
+		return NewTypeScriptRenderer(conf)
+	case "cue":
+		return NewCueRenderer(conf)
+	case "mermaid":
+		return NewMermaidRenderer(conf)
+	case "dot":
+		return NewDOTRenderer(conf)
 	default:
 		return nil, fmt.Errorf("unknown renderer: %s", conf.Renderer)
 	}
@@ -64,15 +83,119 @@ func combinedFuncMap(funcs ...funcMap) template.FuncMap {
 	return m
 }
 
-func createOutFile(outputPath string, defaultFileName string) (*os.File, error) {
-	finfo, err := os.Stat(outputPath)
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
+// declaredTypes returns the named struct and alias types of the given group version, along with
+// the ones they refer to from other packages, sorted by name. Types whose key is in wellKnown are
+// not declared, nor are the types they refer to.
+func declaredTypes[T any](gv types.GroupVersionDetails, wellKnown map[string]T) []*types.Type {
+	seen := make(map[string]bool)
+	var decls []*types.Type
+
+	var visit func(t *types.Type)
+	visit = func(t *types.Type) {
+		if t == nil {
+			return
+		}
+
+		switch t.Kind {
+		case types.PointerKind, types.SliceKind, types.ArrayKind:
+			visit(t.UnderlyingType)
+			return
+		case types.MapKind:
+			visit(t.ValueType)
+			return
+		case types.StructKind, types.AliasKind:
+		default:
+			return
+		}
+
+		if t.Name == "" {
+			// anonymous structs are declared inline
+			for _, f := range t.Members() {
+				visit(f.Type)
+			}
+			return
+		}
+
+		key := types.Key(t)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if _, ok := wellKnown[key]; ok {
+			return
+		}
+
+		decls = append(decls, t)
+		visit(t.UnderlyingType)
+		for _, f := range t.Members() {
+			visit(f.Type)
+		}
+	}
+
+	for _, t := range gv.SortedTypes() {
+		visit(t)
+	}
+
+	sort.SliceStable(decls, func(i, j int) bool {
+		return decls[i].Name < decls[j].Name
+	})
+	return decls
+}
+
+// declaredNames returns the names to declare the given types under, by key. Types are declared
+// under their own name unless it is shared by several of them, in which case the name is prefixed
+// with as many trailing elements of their package path as needed to tell them apart, e.g.
+// V1ObjectMeta and V1beta1ObjectMeta.
+func declaredNames(decls []*types.Type) map[string]string {
+	byName := make(map[string][]*types.Type)
+	for _, t := range decls {
+		byName[t.Name] = append(byName[t.Name], t)
 	}
 
-	if finfo != nil && finfo.IsDir() {
-		outputPath = filepath.Join(outputPath, defaultFileName)
+	names := make(map[string]string, len(decls))
+	for name, colliding := range byName {
+		if len(colliding) == 1 {
+			names[types.Key(colliding[0])] = name
+			continue
+		}
+
+		for n := 1; ; n++ {
+			prefixes := make(map[string]bool, len(colliding))
+			complete := true
+			for _, t := range colliding {
+				prefix, all := packagePrefix(t.Package, n)
+				prefixes[prefix] = true
+				complete = complete && all
+			}
+			if len(prefixes) < len(colliding) && !complete {
+				continue
+			}
+			for _, t := range colliding {
+				prefix, _ := packagePrefix(t.Package, n)
+				names[types.Key(t)] = prefix + name
+			}
+			break
+		}
+	}
+	return names
+}
+
+// packagePrefix returns the last n elements of the given package path as a camel-cased identifier
+// prefix, and whether those are all of its elements.
+func packagePrefix(pkg string, n int) (string, bool) {
+	elems := strings.Split(pkg, "/")
+	all := n >= len(elems)
+	if !all {
+		elems = elems[len(elems)-n:]
 	}
 
-	return os.Create(outputPath)
+	var b strings.Builder
+	for _, elem := range elems {
+		for _, word := range identifierSeparatorRegex.Split(elem, -1) {
+			if word != "" {
+				b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+			}
+		}
+	}
+	return b.String(), all
 }
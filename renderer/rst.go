@@ -0,0 +1,183 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/templates"
+	"github.com/elastic/crd-ref-docs/types"
+)
+
+// rstListTableIndent is the indentation of the content of a list-table cell.
+const rstListTableIndent = "       "
+
+var rstEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"`", "\\`",
+	"|", `\|`,
+	"_", `\_`,
+)
+
+type RstRenderer struct {
+	conf *config.Config
+	fileWriter
+	*Functions
+}
+
+func NewRstRenderer(conf *config.Config) (*RstRenderer, error) {
+	baseFuncs, err := NewFunctions(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &RstRenderer{conf: conf, Functions: baseFuncs}, nil
+}
+
+func (r *RstRenderer) Render(gvd []types.GroupVersionDetails) error {
+	funcMap := combinedFuncMap(funcMap{prefix: "rst", funcs: r.ToFuncMap()}, funcMap{funcs: sprig.TxtFuncMap()})
+
+	var tpls fs.FS
+	if r.conf.TemplatesDir != "" {
+		tpls = os.DirFS(r.conf.TemplatesDir)
+	} else {
+		sub, err := fs.Sub(templates.Root, "rst")
+		if err != nil {
+			return err
+		}
+		tpls = sub
+	}
+
+	tmpl, err := loadTemplate(tpls, funcMap)
+	if err != nil {
+		return err
+	}
+
+	f, err := r.createOutFile(r.conf.OutputPath, "out.rst")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return tmpl.ExecuteTemplate(f, mainTemplate, gvd)
+}
+
+func (r *RstRenderer) ToFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"DOT":                r.DOT,
+		"Escape":             r.Escape,
+		"Example":            r.Example,
+		"Examples":           r.Examples,
+		"GroupVersionID":     r.GroupVersionID,
+		"Heading":            r.Heading,
+		"Mermaid":            r.Mermaid,
+		"RenderExternalLink": r.RenderExternalLink,
+		"RenderFieldDoc":     r.RenderFieldDoc,
+		"RenderGVLink":       r.RenderGVLink,
+		"RenderLocalLink":    r.RenderLocalLink,
+		"RenderType":         r.RenderType,
+		"RenderTypeLink":     r.RenderTypeLink,
+		"SafeID":             r.SafeID,
+		"ShouldRenderType":   r.ShouldRenderType,
+		"TypeID":             r.TypeID,
+	}
+}
+
+func (r *RstRenderer) ShouldRenderType(t *types.Type) bool {
+	return t != nil && (t.GVK != nil || len(t.References) > 0)
+}
+
+func (r *RstRenderer) RenderType(t *types.Type) string {
+	var sb strings.Builder
+	switch t.Kind {
+	case types.MapKind:
+		sb.WriteString("object (")
+		sb.WriteString("keys:")
+		sb.WriteString(r.RenderTypeLink(t.KeyType))
+		sb.WriteString(", values:")
+		sb.WriteString(r.RenderTypeLink(t.ValueType))
+		sb.WriteString(")")
+	case types.ArrayKind, types.SliceKind:
+		sb.WriteString(r.RenderTypeLink(t.UnderlyingType))
+		sb.WriteString(" array")
+	default:
+		sb.WriteString(r.RenderTypeLink(t))
+	}
+
+	return sb.String()
+}
+
+func (r *RstRenderer) RenderTypeLink(t *types.Type) string {
+	text := r.SimplifiedTypeName(t)
+
+	link, local := r.LinkForType(t)
+	if link == "" {
+		return r.Escape(text)
+	}
+
+	if local {
+		return r.RenderLocalLink(link, text)
+	} else {
+		return r.RenderExternalLink(link, text)
+	}
+}
+
+func (r *RstRenderer) RenderLocalLink(label, text string) string {
+	return fmt.Sprintf(":ref:`%s <%s>`", rstEscapeLinkText(text), label)
+}
+
+func (r *RstRenderer) RenderExternalLink(link, text string) string {
+	// anonymous hyperlink references avoid duplicate target names when the same link appears twice
+	return fmt.Sprintf("`%s <%s>`__", rstEscapeLinkText(text), link)
+}
+
+func (r *RstRenderer) RenderGVLink(gv types.GroupVersionDetails) string {
+	return r.RenderLocalLink(r.GroupVersionID(gv), gv.GroupVersionString())
+}
+
+// Heading underlines the given title with the given character, as long as the title itself.
+func (r *RstRenderer) Heading(char, title string) string {
+	return title + "\n" + strings.Repeat(char, len([]rune(title)))
+}
+
+// Escape escapes characters that have a special meaning in inline reStructuredText markup.
+func (r *RstRenderer) Escape(text string) string {
+	return rstEscaper.Replace(text)
+}
+
+// RenderFieldDoc escapes the given text and indents its continuation lines so that the whole
+// text stays in the description cell of a list-table.
+func (r *RstRenderer) RenderFieldDoc(text string) string {
+	lines := strings.Split(r.Escape(text), "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = rstListTableIndent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rstEscapeLinkText escapes the characters that would end the text of a link prematurely.
+func rstEscapeLinkText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`", "<", `\<`).Replace(text)
+}
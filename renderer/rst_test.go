@@ -0,0 +1,33 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRstRendererFieldDoc(t *testing.T) {
+	r, err := NewRstRenderer(&config.Config{})
+	require.NoError(t, err)
+
+	require.Equal(t, `a \*b\* \| c\_d`, r.Escape("a *b* | c_d"))
+	require.Equal(t, "first line\n       second \\`line\\`\n\n       third", r.RenderFieldDoc("first line\nsecond `line`\n\nthird"))
+	require.Equal(t, "Title\n=====", r.Heading("=", "Title"))
+}
@@ -0,0 +1,273 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/templates"
+	"github.com/elastic/crd-ref-docs/types"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+)
+
+var typescriptIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typescriptWellKnownTypes holds the TypeScript types of types whose Go representation differs
+// from their serialized form.
+var typescriptWellKnownTypes = map[string]string{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                          "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                     "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                      "string",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                    "{ name?: string; namespace?: string; labels?: Record<string, string>; annotations?: Record<string, string>; [key: string]: unknown }",
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                      "string | number",
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                    "string | number",
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                       "Record<string, unknown>",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON":      "unknown",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1.JSON": "unknown",
+}
+
+// TypeScriptRenderer writes a TypeScript declaration file per group version, laid out as
+// <group>/<version>.d.ts in the output directory.
+type TypeScriptRenderer struct {
+	conf *config.Config
+	// names holds the declared names of the types of the group version being rendered, by key.
+	names map[string]string
+	fileWriter
+}
+
+func NewTypeScriptRenderer(conf *config.Config) (*TypeScriptRenderer, error) {
+	return &TypeScriptRenderer{conf: conf}, nil
+}
+
+func (ts *TypeScriptRenderer) Render(gvd []types.GroupVersionDetails) error {
+	funcMap := combinedFuncMap(funcMap{prefix: "typescript", funcs: ts.ToFuncMap()}, funcMap{funcs: sprig.TxtFuncMap()})
+
+	var tpls fs.FS
+	if ts.conf.TemplatesDir != "" {
+		tpls = os.DirFS(ts.conf.TemplatesDir)
+	} else {
+		sub, err := fs.Sub(templates.Root, "typescript")
+		if err != nil {
+			return err
+		}
+		tpls = sub
+	}
+
+	tmpl, err := loadTemplate(tpls, funcMap)
+	if err != nil {
+		return err
+	}
+
+	for _, gv := range gvd {
+		ts.names = declaredNames(ts.Declarations(gv))
+		if err := ts.executeToFile(tmpl, "gvDetails", gv, filepath.Join(ts.conf.OutputPath, gv.Group), gv.Version+".d.ts"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ts *TypeScriptRenderer) ToFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"Comment":      ts.Comment,
+		"Declarations": ts.Declarations,
+		"FieldComment": ts.FieldComment,
+		"FieldName":    ts.FieldName,
+		"Fields":       ts.Fields,
+		"IsInterface":  ts.IsInterface,
+		"Name":         ts.Name,
+		"RenderAlias":  ts.RenderAlias,
+		"RenderType":   ts.RenderType,
+	}
+}
+
+// Declarations returns the named types to declare for the given group version: its own types and
+// the types they refer to from other packages, sorted by name.
+func (ts *TypeScriptRenderer) Declarations(gv types.GroupVersionDetails) []*types.Type {
+	return declaredTypes(gv, typescriptWellKnownTypes)
+}
+
+// Name returns the name the given type is declared under, qualified by its package when several
+// declared types share its name.
+func (ts *TypeScriptRenderer) Name(t *types.Type) string {
+	if name, ok := ts.names[types.Key(t)]; ok {
+		return name
+	}
+	return t.Name
+}
+
+// IsInterface returns true if the given type is declared as an interface rather than a type alias.
+func (ts *TypeScriptRenderer) IsInterface(t *types.Type) bool {
+	return t.Kind == types.StructKind
+}
+
+// Fields returns the fields of the given type, except for the apiVersion and kind of Kinds which
+// are declared separately.
+func (ts *TypeScriptRenderer) Fields(t *types.Type) types.Fields {
+	var fields types.Fields
+	for _, f := range t.Members() {
+		if f.Name == "" || (t.GVK != nil && (f.Name == "apiVersion" || f.Name == "kind")) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// FieldName returns the name of a property, quoted if it is not a valid identifier.
+func (ts *TypeScriptRenderer) FieldName(name string) string {
+	if typescriptIdentifierRegex.MatchString(name) {
+		return name
+	}
+	return jsonString(name)
+}
+
+// RenderType returns the TypeScript type of a value of the given type.
+func (ts *TypeScriptRenderer) RenderType(t *types.Type) string {
+	if t == nil {
+		return "unknown"
+	}
+
+	if tsType, ok := typescriptWellKnownTypes[types.Key(t)]; ok {
+		return tsType
+	}
+
+	switch t.Kind {
+	case types.PointerKind:
+		return ts.RenderType(t.UnderlyingType)
+
+	case types.SliceKind, types.ArrayKind:
+		if e := t.UnderlyingType; e != nil && e.Kind == types.BasicKind && (e.Name == "byte" || e.Name == "uint8") {
+			// byte slices are serialized as base64 strings
+			return "string"
+		}
+		elem := ts.RenderType(t.UnderlyingType)
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+
+	case types.MapKind:
+		return "Record<string, " + ts.RenderType(t.ValueType) + ">"
+
+	case types.BasicKind:
+		switch t.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune", "float32", "float64":
+			return "number"
+		default:
+			return "unknown"
+		}
+
+	case types.StructKind:
+		if t.Name != "" {
+			return ts.Name(t)
+		}
+
+		var sb strings.Builder
+		sb.WriteString("{ ")
+		for _, f := range ts.Fields(t) {
+			sb.WriteString(ts.FieldName(f.Name))
+			if f.Optional {
+				sb.WriteString("?")
+			}
+			sb.WriteString(": ")
+			sb.WriteString(ts.RenderType(f.Type))
+			sb.WriteString("; ")
+		}
+		sb.WriteString("}")
+		return sb.String()
+
+	case types.AliasKind:
+		return ts.Name(t)
+
+	default:
+		return "unknown"
+	}
+}
+
+// RenderAlias returns the TypeScript type that an alias type is declared as: a union of string
+// literals for enums, or the type it is an alias of.
+func (ts *TypeScriptRenderer) RenderAlias(t *types.Type) string {
+	if enum, ok := t.Markers.Get(types.EnumMarker).(crdmarkers.Enum); ok && len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = jsonString(v)
+		}
+		return strings.Join(values, " | ")
+	}
+
+	return ts.RenderType(t.UnderlyingType)
+}
+
+// Comment returns the TSDoc comment holding the given documentation, with every line prefixed by
+// indent, or an empty string if there is no documentation.
+func (ts *TypeScriptRenderer) Comment(doc string, indent string) string {
+	return typescriptComment(strings.Split(strings.TrimSpace(doc), "\n"), indent)
+}
+
+// FieldComment returns the TSDoc comment of a field, including its default value.
+func (ts *TypeScriptRenderer) FieldComment(f *types.Field, indent string) string {
+	lines := strings.Split(strings.TrimSpace(f.Doc), "\n")
+	if f.Default != nil {
+		lines = append(lines, "@defaultValue "+jsonString(f.Default))
+	}
+	return typescriptComment(lines, indent)
+}
+
+func typescriptComment(lines []string, indent string) string {
+	if len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(indent + "/**\n")
+	for _, l := range lines {
+		l = strings.TrimSpace(strings.ReplaceAll(l, "*/", "*\\/"))
+		if l == "" {
+			sb.WriteString(indent + " *\n")
+		} else {
+			sb.WriteString(indent + " * " + l + "\n")
+		}
+	}
+	sb.WriteString(indent + " */\n")
+	return sb.String()
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
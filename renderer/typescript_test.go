@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elastic/crd-ref-docs/config"
+	"github.com/elastic/crd-ref-docs/types"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+func TestTypeScriptRenderer(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	level := &types.Type{
+		Name:           "Level",
+		Package:        "example.com/api/v1",
+		Doc:            "Level of the thing.",
+		Kind:           types.AliasKind,
+		UnderlyingType: str,
+		Markers:        markers.MarkerValues{types.EnumMarker: {crdmarkers.Enum{"low", "high"}}},
+	}
+	spec := &types.Type{Name: "ThingSpec", Package: "example.com/api/v1", Kind: types.StructKind}
+	spec.Fields = types.Fields{
+		{Name: "level", Type: level, Doc: "Level to use.", Default: "low", Optional: true},
+		{Name: "sizes", Type: &types.Type{Kind: types.SliceKind, UnderlyingType: &types.Type{Name: "Quantity", Package: "k8s.io/apimachinery/pkg/api/resource", Kind: types.StructKind}}},
+		{Name: "labels", Type: &types.Type{Kind: types.MapKind, KeyType: str, ValueType: str}, Optional: true},
+		{Name: "x-name", Type: str},
+	}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		GVK:     &schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Thing"},
+		Fields:  types.Fields{{Name: "kind", Type: str}, {Name: "spec", Type: spec}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Kinds:        []string{"Thing"},
+		Types:        types.TypeMap{"Thing": thing, "ThingSpec": spec},
+	}}
+
+	dir := t.TempDir()
+	r, err := NewTypeScriptRenderer(&config.Config{Flags: config.Flags{OutputPath: dir}})
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+
+	out, err := os.ReadFile(filepath.Join(dir, "example.com", "v1.d.ts"))
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by crd-ref-docs. DO NOT EDIT.
+
+/**
+ * Types of the example.com/v1 API.
+ */
+
+/**
+ * Level of the thing.
+ */
+export type Level = "low" | "high";
+
+export interface Thing {
+  apiVersion: "example.com/v1";
+  kind: "Thing";
+  spec: ThingSpec;
+}
+
+export interface ThingSpec {
+  /**
+   * Level to use.
+   * @defaultValue "low"
+   */
+  level?: Level;
+  sizes: (string | number)[];
+  labels?: Record<string, string>;
+  "x-name": string;
+}
+`, string(out))
+}
+
+func TestTypeScriptRendererCollidingNames(t *testing.T) {
+	str := &types.Type{Name: "string", Kind: types.BasicKind}
+	v1Status := &types.Type{Name: "Status", Package: "example.com/api/v1", Kind: types.StructKind, Fields: types.Fields{{Name: "phase", Type: str}}}
+	otherStatus := &types.Type{Name: "Status", Package: "example.com/other/v1", Kind: types.StructKind, Fields: types.Fields{{Name: "ready", Type: str}}}
+	thing := &types.Type{
+		Name:    "Thing",
+		Package: "example.com/api/v1",
+		Kind:    types.StructKind,
+		Fields:  types.Fields{{Name: "status", Type: v1Status}, {Name: "otherStatus", Type: otherStatus}},
+	}
+	gvd := []types.GroupVersionDetails{{
+		GroupVersion: schema.GroupVersion{Group: "example.com", Version: "v1"},
+		Types:        types.TypeMap{"Thing": thing, "Status": v1Status},
+	}}
+
+	dir := t.TempDir()
+	r, err := NewTypeScriptRenderer(&config.Config{Flags: config.Flags{OutputPath: dir}})
+	require.NoError(t, err)
+	require.NoError(t, r.Render(gvd))
+
+	out, err := os.ReadFile(filepath.Join(dir, "example.com", "v1.d.ts"))
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by crd-ref-docs. DO NOT EDIT.
+
+/**
+ * Types of the example.com/v1 API.
+ */
+
+export interface ApiV1Status {
+  phase: string;
+}
+
+export interface OtherV1Status {
+  ready: string;
+}
+
+export interface Thing {
+  status: ApiV1Status;
+  otherStatus: OtherV1Status;
+}
+`, string(out))
+}
@@ -0,0 +1,206 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+// Package serve previews rendered documentation over HTTP, reloading the browser when it is
+// rebuilt.
+package serve
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"go.uber.org/zap"
+)
+
+const eventsPath = "/_events"
+
+// reloadScript reloads the page when the server notifies that the documentation was rebuilt.
+const reloadScript = `<script>new EventSource("` + eventsPath + `").onmessage = function () { location.reload(); };</script>`
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// BuildFunc builds the documentation, returning the content of the files keyed by their path.
+type BuildFunc func() (map[string][]byte, error)
+
+// Server serves the files of the last build of the documentation. Markdown files are served as
+// HTML, and HTML pages reload themselves when the documentation is rebuilt.
+type Server struct {
+	build BuildFunc
+	// buildMu serializes the builds.
+	buildMu sync.Mutex
+
+	mu    sync.RWMutex
+	files map[string][]byte
+	err   error
+	// rebuilt is closed and replaced when the documentation is rebuilt.
+	rebuilt chan struct{}
+}
+
+// New returns a server of the documentation built by build. The documentation is built by
+// Rebuild.
+func New(build BuildFunc) *Server {
+	return &Server{build: build, rebuilt: make(chan struct{})}
+}
+
+// Rebuild builds the documentation and notifies the browsers to reload it. Build errors are shown
+// in place of the documentation until the next successful build.
+func (s *Server) Rebuild() {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	files, err := s.build()
+	if err != nil {
+		zap.S().Errorw("Failed to build documentation", "error", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.files = make(map[string][]byte, len(files))
+		for p, content := range files {
+			s.files[path.Clean("/"+p)] = content
+		}
+	}
+	s.err = err
+	close(s.rebuilt)
+	s.rebuilt = make(chan struct{})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == eventsPath {
+		s.serveEvents(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	files, buildErr := s.files, s.err
+	s.mu.RUnlock()
+
+	if buildErr != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "<!DOCTYPE html>\n<html><body><h1>Build failed</h1><pre>%s</pre>%s</body></html>\n", html.EscapeString(buildErr.Error()), reloadScript)
+		return
+	}
+
+	p := path.Clean(r.URL.Path)
+	if p == "/" {
+		p = indexPath(files)
+	}
+
+	content, ok := files[p]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch path.Ext(p) {
+	case ".md":
+		var buf bytes.Buffer
+		if err := markdown.Convert(content, &buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n", html.EscapeString(path.Base(p)))
+		w.Write(buf.Bytes())
+		fmt.Fprintf(w, "%s\n</body>\n</html>\n", reloadScript)
+	case ".html", ".htm":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(injectReloadScript(content))
+	default:
+		if ct := mime.TypeByExtension(path.Ext(p)); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		}
+		w.Write(content)
+	}
+}
+
+// serveEvents sends a server-sent event every time the documentation is rebuilt.
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		s.mu.RLock()
+		rebuilt := s.rebuilt
+		s.mu.RUnlock()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-rebuilt:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// indexPath returns the path of the page served at the root: index.html if there is one, or the
+// first HTML or Markdown file otherwise.
+func indexPath(files map[string][]byte) string {
+	if _, ok := files["/index.html"]; ok {
+		return "/index.html"
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		switch path.Ext(p) {
+		case ".html", ".htm", ".md":
+			return p
+		}
+	}
+	return "/"
+}
+
+// injectReloadScript adds the reload script at the end of the body of the page.
+func injectReloadScript(page []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(page), []byte("</body>"))
+	if i < 0 {
+		return append(append([]byte(nil), page...), reloadScript...)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(page[:i])
+	buf.WriteString(reloadScript)
+	buf.Write(page[i:])
+	return buf.Bytes()
+}
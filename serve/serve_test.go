@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package serve
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, s *Server, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestServer(t *testing.T) {
+	var buildErr error
+	s := New(func() (map[string][]byte, error) {
+		return map[string][]byte{
+			"/out.md":   []byte("# API Reference\n\n| Field | Description |\n| --- | --- |\n| `a` | A. |\n"),
+			"/logo.svg": []byte("<svg/>"),
+		}, buildErr
+	})
+	s.Rebuild()
+
+	code, body := get(t, s, "/")
+	require.Equal(t, http.StatusOK, code)
+	require.Contains(t, body, `<h1 id="api-reference">API Reference</h1>`)
+	require.Contains(t, body, "<td><code>a</code></td>")
+	require.Contains(t, body, reloadScript+"\n</body>")
+
+	code, body = get(t, s, "/logo.svg")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "<svg/>", body)
+
+	code, _ = get(t, s, "/missing.md")
+	require.Equal(t, http.StatusNotFound, code)
+
+	rebuilt := s.rebuilt
+	buildErr = errors.New("template: type.tpl:3: unexpected EOF")
+	s.Rebuild()
+	require.NotEqual(t, rebuilt, s.rebuilt)
+
+	code, body = get(t, s, "/")
+	require.Equal(t, http.StatusInternalServerError, code)
+	require.Contains(t, body, "<pre>template: type.tpl:3: unexpected EOF</pre>")
+	require.Contains(t, body, reloadScript)
+}
+
+func TestInjectReloadScript(t *testing.T) {
+	require.Equal(t, "<html><body><p>doc</p>"+reloadScript+"</BODY></html>",
+		string(injectReloadScript([]byte("<html><body><p>doc</p></BODY></html>"))))
+	require.Equal(t, "<p>doc</p>"+reloadScript, string(injectReloadScript([]byte("<p>doc</p>"))))
+}
@@ -0,0 +1,106 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package serve
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// debounceDelay is how long to wait for changes to settle before calling the change handler, as
+// editors and git write several files in a row.
+const debounceDelay = 250 * time.Millisecond
+
+// Watch calls onChange when files are created, written, removed or renamed under the given paths,
+// until done is closed. Directories are watched recursively, except hidden ones such as .git.
+// Paths that cannot be watched, such as missing ones, are skipped with a warning.
+func Watch(paths []string, onChange func(), done <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for _, p := range paths {
+		if err := watchTree(watcher, p); err != nil {
+			zap.S().Warnw("Failed to watch path", "path", p, "error", err)
+		}
+	}
+
+	var timer *time.Timer
+	for {
+		select {
+		case <-done:
+			return nil
+		case err := <-watcher.Errors:
+			zap.S().Warnw("Failed to watch files", "error", err)
+		case event := <-watcher.Events:
+			if isHidden(event.Name) {
+				continue
+			}
+			zap.S().Debugw("File changed", "path", event.Name, "op", event.Op.String())
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						zap.S().Warnw("Failed to watch directory", "path", event.Name, "error", err)
+					}
+				}
+			}
+
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(debounceDelay, onChange)
+		}
+	}
+}
+
+// watchTree watches the directory at root and its subdirectories. Files are watched through
+// their directory, as editors often replace files rather than write them.
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return watcher.Add(filepath.Dir(root))
+	}
+
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && isHidden(p) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
+
+func isHidden(p string) bool {
+	return strings.HasPrefix(filepath.Base(p), ".")
+}
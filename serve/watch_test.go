@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package serve
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchSkipsMissingPaths(t *testing.T) {
+	dir := t.TempDir()
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- Watch([]string{filepath.Join(dir, "missing"), dir}, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}, done)
+	}()
+
+	// give the watcher time to start before changing the watched directory
+	require.Eventually(t, func() bool {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "types.go"), []byte("package v1\n"), 0o644))
+		select {
+		case <-changed:
+			return true
+		case <-time.After(2 * debounceDelay):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(done)
+	require.NoError(t, <-watchErr)
+}
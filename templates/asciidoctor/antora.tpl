@@ -0,0 +1,44 @@
+{{- define "antoraIndex" -}}
+{{- $groupVersions := . -}}
+
+// Generated documentation. Please do not edit.
+[id="{{ asciidocAnchorPrefix }}-api-reference"]
+= API Reference
+
+.Packages
+{{- range $groupVersions }}
+- {{ asciidocRenderGVLink . }}
+{{- end }}
+{{ end -}}
+
+{{- define "antoraPage" -}}
+{{- $gv := . -}}
+
+// Generated documentation. Please do not edit.
+[id="{{ asciidocGroupVersionID $gv | asciidocRenderAnchorID }}"]
+= {{ $gv.GroupVersionString }}
+
+{{ $gv.Doc }}
+
+{{- if $gv.Kinds  }}
+.Resource Types
+{{- range $gv.SortedKinds }}
+- {{ $gv.TypeForKind . | asciidocRenderTypeLink }}
+{{- end }}
+{{ end }}
+
+:leveloffset: -2
+
+{{ range $gv.SortedTypes }}
+{{ template "type" . }}
+{{ end }}
+
+{{- end -}}
+
+{{- define "antoraNav" -}}
+{{- $groupVersions := . -}}
+* xref:index.adoc[API Reference]
+{{- range $groupVersions }}
+** {{ asciidocRenderGVLink . }}
+{{- end }}
+{{ end -}}
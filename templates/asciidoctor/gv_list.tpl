@@ -2,7 +2,7 @@
 {{- $groupVersions := . -}}
 
 // Generated documentation. Please do not edit.
-:anchor_prefix: k8s-api
+:anchor_prefix: {{ asciidocAnchorPrefix }}
 
 [id="{p}-api-reference"]
 == API Reference
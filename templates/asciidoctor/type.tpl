@@ -5,14 +5,24 @@
 [id="{{ asciidocTypeID $type | asciidocRenderAnchorID }}"]
 ==== {{ $type.Name  }} {{ if $type.IsAlias }}({{ asciidocRenderTypeLink $type.UnderlyingType  }}) {{ end }}
 
-{{ $type.Doc }}
+{{ $type.Doc }}{{ with $type.Availability }}
+
+_{{ . }}_{{ end }}{{ with $type.ListKind }}
+
+_List Kind:_ `{{ . }}`{{ end }}
 
 {{ if $type.References -}}
 .Appears In:
 ****
+{{- if $type.FieldReferences }}
+{{- range $type.FieldReferences }}
+- {{ asciidocRenderTypeLink .Type }} `{{ .Field }}`{{ range $i, $path := .Paths }}{{ if $i }},{{ else }}:{{ end }} `{{ $path }}`{{ end }}
+{{- end }}
+{{- else }}
 {{- range $type.SortedReferences }}
 - {{ asciidocRenderTypeLink . }}
 {{- end }}
+{{- end }}
 ****
 {{- end }}
 
@@ -31,5 +41,14 @@
 |===
 {{ end -}}
 
+{{ if $type.GVK }}
+{{- range $example := asciidocExamples $type }}
+.Example{{ with $example.Source }} ({{ . }}){{ end }}
+[source,yaml]
+----
+{{ $example.Content }}----
+{{ end -}}
+{{ end -}}
+
 {{- end -}}
 {{- end -}}
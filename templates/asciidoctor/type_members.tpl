@@ -3,6 +3,8 @@
 {{- if eq $field.Name "metadata" -}}
 Refer to Kubernetes API documentation for fields of `metadata`.
 {{ else -}}
-{{ asciidocRenderFieldDoc $field.Doc }}
+{{ asciidocRenderFieldDoc $field.Doc }}{{ with $field.Availability }}
+
+_{{ . }}_{{ end }}
 {{- end -}}
 {{- end -}}
@@ -0,0 +1,20 @@
+{{- define "type" -}}
+{{- $type := . -}}
+{{- $fields := cueFields $type -}}
+{{ cueComment $type.Doc "" -}}
+{{ if cueIsStruct $type -}}
+#{{ cueName $type }}: {
+{{- if $type.GVK }}
+	apiVersion: {{ cueQuote $type.GVK.GroupVersion.String }}
+	kind:       {{ cueQuote $type.GVK.Kind }}
+{{- end }}
+{{- range $field := $fields }}
+{{ cueComment $field.Doc "\t" }}	{{ cueFieldName $field }}: {{ cueRenderField $field }}
+{{- end }}
+{{- if or $type.GVK $fields }}
+{{ end -}}
+}
+{{- else -}}
+#{{ cueName $type }}: {{ cueRenderAlias $type }}
+{{- end -}}
+{{- end -}}
@@ -0,0 +1,87 @@
+{{- define "style" -}}
+body { margin: 0; display: flex; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; line-height: 1.5; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 90%; }
+.sidebar { position: sticky; top: 0; height: 100vh; overflow-y: auto; box-sizing: border-box; width: 20rem; flex-shrink: 0; padding: 1rem; border-right: 1px solid #d0d7de; background: #f6f8fa; }
+.sidebar ul { list-style: none; padding-left: 1rem; margin: 0.25rem 0; }
+.sidebar > .toc { padding-left: 0; }
+#search { width: 100%; box-sizing: border-box; padding: 0.4rem; border: 1px solid #d0d7de; border-radius: 4px; }
+#search-results { padding-left: 0; border-bottom: 1px solid #d0d7de; padding-bottom: 0.5rem; }
+#search-results li { padding: 0.2rem 0; }
+#search-results .kind { color: #57606a; font-size: 80%; margin-left: 0.4rem; }
+#search-results .doc { display: block; color: #57606a; font-size: 80%; }
+main { flex-grow: 1; min-width: 0; padding: 1rem 2rem; }
+.type { border-top: 1px solid #d0d7de; margin-top: 1.5rem; }
+.doc { white-space: pre-line; margin: 0.25rem 0 0.5rem; }
+.availability { color: #57606a; font-style: italic; margin: 0 0 0.5rem; }
+.underlying { font-weight: normal; }
+.fields { list-style: none; padding-left: 0; }
+.fields .fields { padding-left: 1.5rem; border-left: 2px solid #d0d7de; }
+.field { padding: 0.25rem 0; }
+.field:target > .field-header, .field:target > details > summary { background: #fff8c5; }
+.field-name { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-weight: 600; color: #1f2328; }
+.field-type { color: #57606a; font-style: italic; margin-left: 0.5rem; }
+summary { cursor: pointer; }
+.example pre { background: #f6f8fa; padding: 0.75rem; overflow-x: auto; }
+{{- end -}}
+
+{{- define "script" -}}
+(function () {
+  // open the collapsed sections containing the element referred to by the location hash
+  function reveal() {
+    if (!location.hash) {
+      return;
+    }
+    var el = document.getElementById(decodeURIComponent(location.hash.substring(1)));
+    for (var p = el; p; p = p.parentElement) {
+      if (p.tagName === "DETAILS") {
+        p.open = true;
+      }
+    }
+    if (el) {
+      el.scrollIntoView();
+    }
+  }
+  window.addEventListener("hashchange", reveal);
+  reveal();
+
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.innerHTML = "";
+    results.hidden = terms.length === 0;
+    if (results.hidden) {
+      return;
+    }
+    var matches = searchIndex.filter(function (entry) {
+      var text = (entry.name + " " + entry.group + " " + (entry.doc || "")).toLowerCase();
+      return terms.every(function (term) { return text.indexOf(term) >= 0; });
+    });
+    matches.slice(0, 50).forEach(function (entry) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = "#" + entry.anchor;
+      a.textContent = entry.name;
+      li.appendChild(a);
+      var kind = document.createElement("span");
+      kind.className = "kind";
+      kind.textContent = entry.kind + " · " + entry.group;
+      li.appendChild(kind);
+      if (entry.doc) {
+        var doc = document.createElement("span");
+        doc.className = "doc";
+        doc.textContent = entry.doc;
+        li.appendChild(doc);
+      }
+      results.appendChild(li);
+    });
+    if (matches.length === 0) {
+      var none = document.createElement("li");
+      none.textContent = "No results";
+      results.appendChild(none);
+    }
+  });
+})();
+{{- end -}}
@@ -0,0 +1,22 @@
+{{- define "fields" -}}
+{{- range . }}
+<li class="field" id="{{ .ID }}">
+{{- if .Children }}
+<details>
+<summary>{{ template "field_header" . }}</summary>
+{{ template "type_members" .Field }}
+<ul class="fields">
+{{- template "fields" .Children }}
+</ul>
+</details>
+{{- else }}
+{{ template "field_header" . }}
+{{ template "type_members" .Field }}
+{{- end }}
+</li>
+{{- end }}
+{{- end -}}
+
+{{- define "field_header" -}}
+<span class="field-header"><a class="field-name" href="#{{ .ID }}">{{ .Name }}</a> <span class="field-type">{{ htmlRenderType .Type }}</span></span>
+{{- end -}}
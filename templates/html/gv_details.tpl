@@ -0,0 +1,18 @@
+{{- define "gvDetails" -}}
+{{- $gv := . -}}
+<section class="group-version">
+<h2 id="{{ htmlGroupVersionID $gv }}">{{ $gv.GroupVersionString }}</h2>
+{{ if $gv.Doc }}<p class="doc">{{ $gv.Doc }}</p>{{ end }}
+{{- if $gv.Kinds }}
+<h3>Resource Types</h3>
+<ul>
+{{- range $gv.SortedKinds }}
+<li>{{ $gv.TypeForKind . | htmlRenderTypeLink }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{ range $gv.SortedTypes }}
+{{- template "type" . }}
+{{ end }}
+</section>
+{{- end -}}
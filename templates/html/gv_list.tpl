@@ -0,0 +1,51 @@
+{{- define "gvList" -}}
+{{- $groupVersions := . -}}
+<!DOCTYPE html>
+<!-- Generated documentation. Please do not edit. -->
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>API Reference</title>
+<style>
+{{ template "style" }}
+</style>
+</head>
+<body>
+<nav class="sidebar">
+<input type="search" id="search" placeholder="Search types and fields" autocomplete="off">
+<ul id="search-results" hidden></ul>
+<ul class="toc">
+{{- range $groupVersions }}
+{{- $gv := . }}
+<li>{{ htmlRenderGVLink $gv }}
+{{- if $gv.Kinds }}
+<ul>
+{{- range $gv.SortedKinds }}
+<li>{{ $gv.TypeForKind . | htmlRenderTypeLink }}</li>
+{{- end }}
+</ul>
+{{- end }}
+</li>
+{{- end }}
+</ul>
+</nav>
+<main>
+<h1 id="api-reference">API Reference</h1>
+<h2>Packages</h2>
+<ul>
+{{- range $groupVersions }}
+<li>{{ htmlRenderGVLink . }}</li>
+{{- end }}
+</ul>
+{{ range $groupVersions }}
+{{ template "gvDetails" . }}
+{{ end }}
+</main>
+<script>
+const searchIndex = {{ htmlSearchIndex $groupVersions }};
+{{ template "script" }}
+</script>
+</body>
+</html>
+{{ end -}}
@@ -0,0 +1,42 @@
+{{- define "type" -}}
+{{- $type := . -}}
+{{- if htmlShouldRenderType $type -}}
+<section class="type">
+<h4 id="{{ htmlTypeID $type }}">{{ $type.Name }}{{ if $type.IsAlias }} <span class="underlying">({{ htmlRenderTypeLink $type.UnderlyingType }})</span>{{ end }}</h4>
+{{ if $type.Doc }}<p class="doc">{{ $type.Doc }}</p>{{ end }}{{ with $type.Availability }}<p class="availability">{{ . }}</p>{{ end }}{{ with $type.ListKind }}<p class="list-kind"><em>List Kind:</em> <code>{{ . }}</code></p>{{ end }}
+{{- if $type.References }}
+<div class="appears-in">
+<em>Appears in:</em>
+<ul>
+{{- if $type.FieldReferences }}
+{{- range $type.FieldReferences }}
+<li>{{ htmlRenderTypeLink .Type }} <code>{{ .Field }}</code>{{ range $i, $path := .Paths }}{{ if $i }},{{ else }}:{{ end }} <code>{{ $path }}</code>{{ end }}</li>
+{{- end }}
+{{- else }}
+{{- range $type.SortedReferences }}
+<li>{{ htmlRenderTypeLink . }}</li>
+{{- end }}
+{{- end }}
+</ul>
+</div>
+{{- end }}
+{{- if $type.Members }}
+<ul class="fields">
+{{- if $type.GVK }}
+<li class="field" id="{{ htmlFieldID $type "apiVersion" }}"><span class="field-header"><a class="field-name" href="#{{ htmlFieldID $type "apiVersion" }}">apiVersion</a> <span class="field-type">string</span></span><p class="doc"><code>{{ $type.GVK.Group }}/{{ $type.GVK.Version }}</code></p></li>
+<li class="field" id="{{ htmlFieldID $type "kind" }}"><span class="field-header"><a class="field-name" href="#{{ htmlFieldID $type "kind" }}">kind</a> <span class="field-type">string</span></span><p class="doc"><code>{{ $type.GVK.Kind }}</code></p></li>
+{{- end }}
+{{- template "fields" htmlFieldTree $type }}
+</ul>
+{{- end }}
+{{- if $type.GVK }}
+{{- range $example := htmlExamples $type }}
+<details class="example">
+<summary>Example{{ with $example.Source }} ({{ . }}){{ end }}</summary>
+<pre><code>{{ $example.Content }}</code></pre>
+</details>
+{{- end }}
+{{- end }}
+</section>
+{{- end -}}
+{{- end -}}
@@ -0,0 +1,9 @@
+{{- define "type_members" -}}
+{{- $field := . -}}
+{{- if eq $field.Name "metadata" -}}
+<p class="doc">Refer to Kubernetes API documentation for fields of <code>metadata</code>.</p>
+{{- else if $field.Doc -}}
+<p class="doc">{{ $field.Doc }}</p>
+{{- end -}}
+{{- with $field.Availability }}<p class="availability">{{ . }}</p>{{ end -}}
+{{- end -}}
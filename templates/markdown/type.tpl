@@ -6,14 +6,24 @@
 
 {{ if $type.IsAlias }}_Underlying type:_ `{{ markdownRenderTypeLink $type.UnderlyingType  }}`{{ end }}
 
-{{ $type.Doc }}
+{{ $type.Doc }}{{ with $type.Availability }}
+
+_{{ . }}_{{ end }}{{ with $type.ListKind }}
+
+_List Kind:_ `{{ . }}`{{ end }}
 
 {{ if $type.References -}}
 _Appears in:_
+{{- if $type.FieldReferences }}
+{{- range $type.FieldReferences }}
+- {{ markdownRenderTypeLink .Type }} `{{ .Field }}`{{ range $i, $path := .Paths }}{{ if $i }},{{ else }}:{{ end }} `{{ $path }}`{{ end }}
+{{- end }}
+{{- else }}
 {{- range $type.SortedReferences }}
 - {{ markdownRenderTypeLink . }}
 {{- end }}
 {{- end }}
+{{- end }}
 
 {{ if $type.Members -}}
 | Field | Description |
@@ -29,5 +39,15 @@ _Appears in:_
 
 {{ end -}}
 
+{{ if $type.GVK -}}
+{{ range $example := markdownExamples $type -}}
+_Example{{ with $example.Source }} ({{ . }}){{ end }}:_
+
+```yaml
+{{ $example.Content }}```
+
+{{ end -}}
+{{ end -}}
+
 {{- end -}}
 {{- end -}}
@@ -0,0 +1,23 @@
+{{- define "gvDetails" -}}
+{{- $gv := . -}}
+
+.. _{{ rstGroupVersionID $gv }}:
+
+{{ rstHeading "-" $gv.GroupVersionString }}
+
+{{ rstEscape $gv.Doc }}
+
+{{- if $gv.Kinds  }}
+
+.. rubric:: Resource Types
+
+{{ range $gv.SortedKinds -}}
+- {{ $gv.TypeForKind . | rstRenderTypeLink }}
+{{ end }}
+{{- end }}
+
+{{ range $gv.SortedTypes }}
+{{ template "type" . }}
+{{ end }}
+
+{{- end -}}
@@ -0,0 +1,20 @@
+{{- define "gvList" -}}
+{{- $groupVersions := . -}}
+
+.. Generated documentation. Please do not edit.
+
+.. _api-reference:
+
+{{ rstHeading "=" "API Reference" }}
+
+.. rubric:: Packages
+
+{{ range $groupVersions -}}
+- {{ rstRenderGVLink . }}
+{{ end }}
+
+{{ range $groupVersions }}
+{{ template "gvDetails" . }}
+{{ end }}
+
+{{- end -}}
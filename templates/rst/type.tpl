@@ -0,0 +1,61 @@
+{{- define "type" -}}
+{{- $type := . -}}
+{{- if rstShouldRenderType $type -}}
+
+.. _{{ rstTypeID $type }}:
+
+{{ rstHeading "~" $type.Name }}
+
+{{ if $type.IsAlias }}*Underlying type:* {{ rstRenderTypeLink $type.UnderlyingType }}{{ end }}
+
+{{ rstEscape $type.Doc }}{{ with $type.Availability }}
+
+*{{ rstEscape . }}*{{ end }}{{ with $type.ListKind }}
+
+*List Kind:* ``{{ . }}``{{ end }}
+
+{{ if $type.References -}}
+*Appears in:*
+
+{{ if $type.FieldReferences -}}
+{{ range $type.FieldReferences -}}
+- {{ rstRenderTypeLink .Type }} ``{{ .Field }}``{{ range $i, $path := .Paths }}{{ if $i }},{{ else }}:{{ end }} ``{{ $path }}``{{ end }}
+{{ end }}
+{{- else -}}
+{{ range $type.SortedReferences -}}
+- {{ rstRenderTypeLink . }}
+{{ end }}
+{{- end }}
+{{- end }}
+
+{{ if $type.Members -}}
+.. list-table::
+   :header-rows: 1
+   :widths: 30 70
+
+   * - Field
+     - Description
+{{- if $type.GVK }}
+   * - ``apiVersion`` (string)
+     - ``{{ $type.GVK.Group }}/{{ $type.GVK.Version }}``
+   * - ``kind`` (string)
+     - ``{{ $type.GVK.Kind }}``
+{{- end }}
+{{- range $type.Members }}
+   * - ``{{ .Name }}`` ({{ rstRenderType .Type }})
+     - {{ template "type_members" . }}
+{{- end }}
+{{ end -}}
+
+{{ if $type.GVK -}}
+{{ range $example := rstExamples $type -}}
+.. rubric:: Example{{ with $example.Source }} ({{ rstEscape . }}){{ end }}
+
+.. code-block:: yaml
+
+{{ $example.Content | trimSuffix "\n" | indent 3 }}
+{{ end -}}
+{{ end -}}
+
+{{- end -}}
+{{- end -}}
@@ -0,0 +1,8 @@
+{{- define "type_members" -}}
+{{- $field := . -}}
+{{- if eq $field.Name "metadata" -}}
+Refer to Kubernetes API documentation for fields of ``metadata``.
+{{- else -}}
+{{ rstRenderFieldDoc $field.Doc }}{{ with $field.Availability }} *{{ rstEscape . }}*{{ end }}
+{{- end -}}
+{{- end -}}
@@ -4,4 +4,8 @@ import "embed"
 
 //go:embed asciidoctor
 //go:embed markdown
+//go:embed html
+//go:embed rst
+//go:embed typescript
+//go:embed cue
 var Root embed.FS
@@ -0,0 +1,20 @@
+{{- define "type" -}}
+{{- $type := . -}}
+{{- $fields := typescriptFields $type -}}
+{{ typescriptComment $type.Doc "" -}}
+{{ if typescriptIsInterface $type -}}
+export interface {{ typescriptName $type }} {
+{{- if $type.GVK }}
+  apiVersion: {{ $type.GVK.GroupVersion.String | quote }};
+  kind: {{ $type.GVK.Kind | quote }};
+{{- end }}
+{{- range $field := $fields }}
+{{ typescriptFieldComment $field "  " }}  {{ typescriptFieldName $field.Name }}{{ if $field.Optional }}?{{ end }}: {{ typescriptRenderType $field.Type }};
+{{- end }}
+{{- if or $type.GVK $fields }}
+{{ end -}}
+}
+{{- else -}}
+export type {{ typescriptName $type }} = {{ typescriptRenderAlias $type }};
+{{- end -}}
+{{- end -}}
@@ -23,7 +23,7 @@ set -euo pipefail
 
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 TEMP_DIR=$(mktemp -d -t crd-ref-docs-XXXXX)
-DEFAULT_ARGS=(--log-level=ERROR --source-path="${SCRIPT_DIR}/test" --output-path="${TEMP_DIR}/out" --config="${SCRIPT_DIR}/test/config.yaml")
+DEFAULT_ARGS=(--log-level=ERROR --source-path="${SCRIPT_DIR}/test" --output-path="${TEMP_DIR}/out")
 
 trap '[[ $TEMP_DIR ]] && rm -rf "$TEMP_DIR"' EXIT
 
@@ -33,6 +33,8 @@ run_test() {
 
     local renderer=asciidoctor
     local templates_dir=
+    local config=config.yaml
+    local expected=
 
     while :; do
         case "${1:-}" in
@@ -54,6 +56,24 @@ run_test() {
                     exit 1
                 fi
                 ;;
+            --config)
+                if [[ -n "${2:-}" ]]; then
+                    config="$2"
+                    shift
+                else
+                    printf "ERROR: '--config' cannot be empty.\n\n" >&2
+                    exit 1
+                fi
+                ;;
+            --expected)
+                if [[ -n "${2:-}" ]]; then
+                    expected="$2"
+                    shift
+                else
+                    printf "ERROR: '--expected' cannot be empty.\n\n" >&2
+                    exit 1
+                fi
+                ;;
             *)
                 break
                 ;;
@@ -62,16 +82,29 @@ run_test() {
         shift
     done
 
-    local args=("${DEFAULT_ARGS[@]}" --renderer="$renderer")
+    local args=("${DEFAULT_ARGS[@]}" --config="${SCRIPT_DIR}/test/${config}" --renderer="$renderer")
     if [[ -n "$templates_dir" ]]; then
         args+=(--templates-dir="$templates_dir")
     fi
 
-    local expected
-    if [[ "$renderer" == "asciidoctor" ]]; then
-        expected=expected.asciidoc
-    else
-        expected=expected.md
+    if [[ -z "$expected" ]]; then
+        case "$renderer" in
+            asciidoctor)
+                expected=expected.asciidoc
+                ;;
+            html)
+                expected=expected.html
+                ;;
+            rst)
+                expected=expected.rst
+                ;;
+            openapi)
+                expected=expected.openapi.json
+                ;;
+            *)
+                expected=expected.md
+                ;;
+        esac
     fi
 
     (
@@ -97,3 +130,10 @@ run_test
 run_test --renderer asciidoctor --templates-dir templates/asciidoctor
 run_test --renderer markdown
 run_test --renderer markdown --templates-dir templates/markdown
+run_test --renderer html
+run_test --renderer html --templates-dir templates/html
+run_test --renderer rst
+run_test --renderer rst --templates-dir templates/rst
+run_test --renderer openapi
+# without samples, the examples of the Kinds are generated from their types
+run_test --renderer markdown --config config-generated-examples.yaml --expected expected-generated-examples.md
@@ -51,6 +51,8 @@ type EmbeddedX struct {
 // GuestbookSpec defines the desired state of Guestbook.
 type GuestbookSpec struct {
 	// Page indicates the page number
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
 	Page *int `json:"page,omitempty"`
 	// Entries contain guest book entries for the page
 	Entries []GuestbookEntry `json:"entries,omitempty"`
@@ -65,6 +67,7 @@ type GuestbookSpec struct {
 // GuestbookEntry defines an entry in a guest book.
 type GuestbookEntry struct {
 	// Name of the guest (pipe | should be escaped)
+	// +kubebuilder:validation:MaxLength=80
 	Name string `json:"name,omitempty"`
 	// Time of entry
 	Time metav1.Time `json:"time,omitempty"`
@@ -103,6 +106,7 @@ type GuestbookList struct {
 }
 
 // Rating is the rating provided by a guest.
+// +kubebuilder:validation:Enum=poor;fair;good;excellent
 type Rating string
 
 func init() {
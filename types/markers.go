@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package types
+
+// Names of the kubebuilder markers constraining the values of types and fields, as found in
+// Type.Markers and Field.Markers.
+const (
+	EmbeddedResourceMarker       = "kubebuilder:validation:EmbeddedResource"
+	EnumMarker                   = "kubebuilder:validation:Enum"
+	ExclusiveMaximumMarker       = "kubebuilder:validation:ExclusiveMaximum"
+	ExclusiveMinimumMarker       = "kubebuilder:validation:ExclusiveMinimum"
+	FormatMarker                 = "kubebuilder:validation:Format"
+	MaximumMarker                = "kubebuilder:validation:Maximum"
+	MaxItemsMarker               = "kubebuilder:validation:MaxItems"
+	MaxLengthMarker              = "kubebuilder:validation:MaxLength"
+	MaxPropertiesMarker          = "kubebuilder:validation:MaxProperties"
+	MinimumMarker                = "kubebuilder:validation:Minimum"
+	MinItemsMarker               = "kubebuilder:validation:MinItems"
+	MinLengthMarker              = "kubebuilder:validation:MinLength"
+	MinPropertiesMarker          = "kubebuilder:validation:MinProperties"
+	MultipleOfMarker             = "kubebuilder:validation:MultipleOf"
+	PatternMarker                = "kubebuilder:validation:Pattern"
+	TypeMarker                   = "kubebuilder:validation:Type"
+	UniqueItemsMarker            = "kubebuilder:validation:UniqueItems"
+	XEmbeddedResourceMarker      = "kubebuilder:validation:XEmbeddedResource"
+	XIntOrStringMarker           = "kubebuilder:validation:XIntOrString"
+	XPreserveUnknownFieldsMarker = "kubebuilder:validation:XPreserveUnknownFields"
+	XValidationMarker            = "kubebuilder:validation:XValidation"
+
+	// PreserveUnknownFieldsMarker is the legacy name of XPreserveUnknownFieldsMarker.
+	PreserveUnknownFieldsMarker = "kubebuilder:pruning:PreserveUnknownFields"
+)
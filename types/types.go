@@ -24,6 +24,7 @@ import (
 
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-tools/pkg/markers"
 )
 
 // Kind describes the kind of the type (alias, array, etc.)
@@ -110,21 +111,75 @@ type Type struct {
 	ValueType      *Type                    `json:"valueType"`      // for maps
 	Fields         Fields                   `json:"fields"`         // for structs
 	References     []*Type                  `json:"-"`              // other types that refer to this type
+	Markers        markers.MarkerValues     `json:"-"`              // markers declared on the type
+	Validation     []string                 `json:"validation"`     // validation rules declared by markers
+	Examples       []Example                `json:"examples"`       // sample manifests, for Kinds
+	ListKind       string                   `json:"listKind"`       // Kind listing this Kind, when List Kinds are collapsed
+	// FieldReferences are the fields of other types that refer to this type, sorted by type and
+	// field name.
+	FieldReferences []FieldReference `json:"-"`
+	History
+}
+
+// FieldReference is a field of a type referring to another type.
+type FieldReference struct {
+	Type  *Type  // type declaring the field
+	Field string // name of the field
+	// Paths of the field from the Kinds of the group version, such as
+	// Guestbook.spec.entries[].rating. Elements of slices and arrays are denoted by [] and values
+	// of maps by {}.
+	Paths []string
+}
+
+// History records the releases of the API in which a Kind, type or field was introduced and
+// deprecated. The attributes are empty when the history is unknown.
+type History struct {
+	Since      string `json:"since,omitempty"`      // first release in which it is available
+	Deprecated string `json:"deprecated,omitempty"` // first release in which it is deprecated
+}
+
+// Availability describes the history in a sentence, such as "Available since v1.2.0.".
+func (h History) Availability() string {
+	var parts []string
+	if h.Since != "" {
+		parts = append(parts, "available since "+h.Since)
+	}
+	if h.Deprecated != "" {
+		parts = append(parts, "deprecated since "+h.Deprecated)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	s := strings.Join(parts, ", ")
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// Example is a sample manifest of a Kind.
+type Example struct {
+	Source  string `json:"source"`  // path of the sample, relative to the samples path
+	Content string `json:"content"` // YAML content of the sample
 }
 
 func (t *Type) Copy() *Type {
 	return &Type{
-		Name:           t.Name,
-		Package:        t.Package,
-		Doc:            t.Doc,
-		GVK:            t.GVK,
-		Kind:           t.Kind,
-		Imported:       t.Imported,
-		UnderlyingType: t.UnderlyingType,
-		KeyType:        t.KeyType,
-		ValueType:      t.ValueType,
-		Fields:         t.Fields,
-		References:     t.References,
+		Name:            t.Name,
+		Package:         t.Package,
+		Doc:             t.Doc,
+		GVK:             t.GVK,
+		Kind:            t.Kind,
+		Imported:        t.Imported,
+		UnderlyingType:  t.UnderlyingType,
+		KeyType:         t.KeyType,
+		ValueType:       t.ValueType,
+		Fields:          t.Fields,
+		References:      t.References,
+		Markers:         t.Markers,
+		Validation:      t.Validation,
+		Examples:        t.Examples,
+		ListKind:        t.ListKind,
+		FieldReferences: t.FieldReferences,
+		History:         t.History,
 	}
 }
 
@@ -160,6 +215,23 @@ func (t *Type) Members() Fields {
 	}
 }
 
+// ElemType returns the type describing the values held by t: the element type of arrays, slices
+// and pointers, the value type of maps, and t itself otherwise.
+func (t *Type) ElemType() *Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind {
+	case ArrayKind, SliceKind, PointerKind:
+		return t.UnderlyingType.ElemType()
+	case MapKind:
+		return t.ValueType.ElemType()
+	default:
+		return t
+	}
+}
+
 func (t *Type) String() string {
 	if t == nil {
 		return "<unknown>"
@@ -267,11 +339,17 @@ func (types TypeMap) InlineTypes(propagateReference func(original *Type, additio
 
 // Field describes a field in a struct.
 type Field struct {
-	Name     string
-	Embedded bool
-	Inlined  bool
-	Doc      string
-	Type     *Type
+	Name       string
+	GoName     string // name of the field in the Go struct, empty for embedded fields
+	Embedded   bool
+	Inlined    bool
+	Doc        string
+	Type       *Type
+	Markers    markers.MarkerValues `json:"-"` // markers declared on the field
+	Validation []string             // validation rules declared by markers
+	Default    any                  // value of the default marker, if any
+	Optional   bool                 // whether the field may be omitted
+	History
 }
 
 type Fields []*Field